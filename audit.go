@@ -0,0 +1,138 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"time"
+)
+
+// This file contains helpers for producing verifiable audit records.
+
+// marshalDecisions returns the canonical JSON encoding of ds used for hashing
+// and signing. Adjust closures are excluded and Reason is reduced to its
+// message (see decisionJSON).
+func marshalDecisions(ds []Decision) ([]byte, error) {
+	out := make([]decisionJSON, len(ds))
+	for i, d := range ds {
+		j, err := toDecisionJSON(d)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = j
+	}
+	return json.Marshal(out)
+}
+
+// HashDecisions returns an HMAC-SHA256 signature of ds keyed by secret,
+// computed over the canonical JSON encoding of the slice. Adjust functions are
+// not part of the encoding, so two decision sets differing only in their
+// Adjust closures produce the same signature.
+//
+// Store the signature next to the audit record; VerifyDecisionsHash detects
+// any later change to policy IDs, actions, scopes, reasons, Stop flags, or
+// ordering.
+func HashDecisions(ds []Decision, secret []byte) ([]byte, error) {
+	data, err := marshalDecisions(ds)
+	if err != nil {
+		return nil, err
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(data)
+	return mac.Sum(nil), nil
+}
+
+// VerifyDecisionsHash reports whether sig is the HashDecisions signature of ds
+// under secret. The comparison is constant-time.
+func VerifyDecisionsHash(ds []Decision, secret, sig []byte) bool {
+	want, err := HashDecisions(ds, secret)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(want, sig)
+}
+
+// Receipt is proof that a set of Decisions was handed to an Enforcer.
+//
+//   - EnforcedAt:    when enforcement completed.
+//   - DecisionCount: how many Decisions were applied (a Stop decision ends
+//     the run, so this may be less than len(ds)).
+//   - Hash:          SHA-256 of the canonical JSON encoding of the applied
+//     Decisions (see HashDecisions for what the encoding covers).
+type Receipt struct {
+	EnforcedAt    time.Time
+	DecisionCount int
+	Hash          []byte
+}
+
+// EnforceWithReceipt applies ds with Enforce and returns a Receipt describing
+// what was applied. Store the receipt alongside the node's state for audit
+// purposes. An error is returned only if the decisions cannot be encoded; in
+// that case nothing is enforced.
+func EnforceWithReceipt(e Enforcer, ds []Decision) (Receipt, error) {
+	applied := ds
+	for i, d := range ds {
+		if d.Stop {
+			applied = ds[:i+1]
+			break
+		}
+	}
+	data, err := marshalDecisions(applied)
+	if err != nil {
+		return Receipt{}, err
+	}
+
+	Enforce(e, applied)
+
+	sum := sha256.Sum256(data)
+	return Receipt{
+		EnforcedAt:    time.Now(),
+		DecisionCount: len(applied),
+		Hash:          sum[:],
+	}, nil
+}
+
+// AuditRecord is the flat, serialisable form of one Decision applied to a
+// node, for shipping to an audit pipeline.
+type AuditRecord struct {
+	NodeID    string    `json:"node_id"`
+	PolicyID  string    `json:"policy_id"`
+	Action    Action    `json:"action"`
+	Scope     Scope     `json:"scope"`
+	Reason    string    `json:"reason,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// NewAuditRecord returns the AuditRecord for d evaluated against n. The
+// Timestamp is d.Timestamp, or the current time if d has none; Reason is
+// d.Reason's message ("" for nil).
+func NewAuditRecord(n Node, d Decision) AuditRecord {
+	rec := AuditRecord{
+		NodeID:    n.ID(),
+		PolicyID:  d.PolicyID,
+		Action:    d.Action,
+		Scope:     d.Scope,
+		Timestamp: d.Timestamp,
+	}
+	if d.Reason != nil {
+		rec.Reason = d.Reason.Error()
+	}
+	if rec.Timestamp.IsZero() {
+		rec.Timestamp = time.Now()
+	}
+	return rec
+}
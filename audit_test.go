@@ -0,0 +1,110 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+func TestHashDecisionsTamperEvident(t *testing.T) {
+	secret := []byte("k")
+	ds := []policy.Decision{
+		{PolicyID: "cap", Action: policy.ActionAdjust, Adjust: func(map[string]any) {}, Reason: policy.Reason("cap")},
+		{PolicyID: "safety", Action: policy.ActionCancelRoot, Scope: policy.ScopeRoot, Stop: true},
+	}
+	sig, err := policy.HashDecisions(ds, secret)
+	if err != nil {
+		t.Fatalf("HashDecisions: %v", err)
+	}
+	if len(sig) != 32 {
+		t.Fatalf("expected a SHA-256 sized signature, got %d bytes", len(sig))
+	}
+	if !policy.VerifyDecisionsHash(ds, secret, sig) {
+		t.Fatalf("signature does not verify")
+	}
+
+	ds[0].Adjust = nil
+	if !policy.VerifyDecisionsHash(ds, secret, sig) {
+		t.Fatalf("Adjust must not be part of the signature")
+	}
+
+	tampered := append([]policy.Decision(nil), ds...)
+	tampered[1].Action = policy.ActionWarn
+	if policy.VerifyDecisionsHash(tampered, secret, sig) {
+		t.Fatalf("tampered action verified")
+	}
+	if policy.VerifyDecisionsHash([]policy.Decision{ds[1], ds[0]}, secret, sig) {
+		t.Fatalf("reordered decisions verified")
+	}
+	if policy.VerifyDecisionsHash(ds, []byte("other"), sig) {
+		t.Fatalf("wrong secret verified")
+	}
+}
+
+func TestEnforceWithReceipt(t *testing.T) {
+	ds := []policy.Decision{
+		{PolicyID: "W", Action: policy.ActionWarn},
+		{PolicyID: "S", Action: policy.ActionCancelRoot, Stop: true},
+		{PolicyID: "X", Action: policy.ActionWarn},
+	}
+	e := &recEnforcer{}
+	before := time.Now()
+	rc, err := policy.EnforceWithReceipt(e, ds)
+	if err != nil {
+		t.Fatalf("EnforceWithReceipt: %v", err)
+	}
+	if rc.DecisionCount != 2 || len(e.warns) != 1 || len(e.cancels) != 1 {
+		t.Fatalf("receipt=%+v warns=%v cancels=%v", rc, e.warns, e.cancels)
+	}
+	if rc.EnforcedAt.Before(before) || len(rc.Hash) != 32 {
+		t.Fatalf("receipt metadata %+v", rc)
+	}
+
+	again, _ := policy.EnforceWithReceipt(&recEnforcer{}, ds[:2])
+	if !bytes.Equal(rc.Hash, again.Hash) {
+		t.Fatalf("hash should cover exactly the applied decisions")
+	}
+}
+
+func TestNewAuditRecord(t *testing.T) {
+	n := &testNode{id: "n1", name: "job"}
+	at := time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC)
+	rec := policy.NewAuditRecord(n, policy.Decision{
+		PolicyID: "p", Scope: policy.ScopeSubtree, Action: policy.ActionWarn,
+		Reason: policy.Reason("slow"), Timestamp: at,
+	})
+	data, err := json.Marshal(rec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"node_id":"n1","policy_id":"p","action":"Warn","scope":"Subtree","reason":"slow","timestamp":"2025-03-04T05:06:07Z"}`
+	if string(data) != want {
+		t.Fatalf("got  %s\nwant %s", data, want)
+	}
+	var back policy.AuditRecord
+	if err := json.Unmarshal(data, &back); err != nil || back != rec {
+		t.Fatalf("round trip: %+v, %v", back, err)
+	}
+
+	before := time.Now()
+	if rec := policy.NewAuditRecord(n, policy.Decision{PolicyID: "p"}); rec.Timestamp.Before(before) || rec.Reason != "" {
+		t.Fatalf("unstamped decision: %+v", rec)
+	}
+}
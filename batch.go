@@ -0,0 +1,72 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+// BatchEvaluate evaluates every node in nodes and returns their Decisions
+// keyed by Node.ID(). Per node the result equals Evaluate(n), with one
+// difference: Match is called only once per (policy, Name()) pair, using the
+// first node of each name as the representative, and the result is reused
+// for every other node with that name.
+//
+// This is only correct when the registered policies' Match depends on
+// nothing but the node name (as with MatchByName). Check still runs for every
+// matching node. Nodes sharing an ID overwrite each other's entry. Panics are
+// recovered as in Evaluate; a panicking Match yields the warning for every
+// node of that name.
+func (r *Registry) BatchEvaluate(nodes []Node) map[string][]Decision {
+	pols := r.snapshot()
+	hooks := r.currentHooks()
+	type matchResult struct {
+		matched bool
+		err     error // a *PanicError from Match
+	}
+	matched := map[string][]matchResult{}
+	out := make(map[string][]Decision, len(nodes))
+
+	for _, n := range nodes {
+		m, ok := matched[n.Name()]
+		if !ok {
+			m = make([]matchResult, len(pols))
+			for i, p := range pols {
+				m[i].matched, m[i].err = safeMatch(p, n)
+			}
+			matched[n.Name()] = m
+		}
+
+		var ds []Decision
+	policies:
+		for i, p := range pols {
+			if m[i].err == nil && !m[i].matched {
+				continue
+			}
+			for _, d := range r.checkOrWarn(p, m[i].err, func() []Decision { return checkPolicy(p, n) }) {
+				ds = append(ds, d)
+				if d.Stop {
+					if hooks.OnStop != nil {
+						hooks.OnStop(d, pols[i+1:])
+					}
+					break policies
+				}
+			}
+		}
+		out[n.ID()] = ds
+	}
+	return out
+}
+
+// BatchEvaluate runs Registry.BatchEvaluate on the global registry.
+func BatchEvaluate(nodes []Node) map[string][]Decision {
+	return defaultRegistry.BatchEvaluate(nodes)
+}
@@ -0,0 +1,66 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"reflect"
+	"testing"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+func TestBatchEvaluateMatchesOncePerName(t *testing.T) {
+	p := &matchCounter{warnPolicy: warnPolicy{id: "m"}}
+	r := policy.New()
+	r.Register(p)
+
+	nodes := []policy.Node{
+		&testNode{id: "w1", name: "Worker"},
+		&testNode{id: "w2", name: "Worker"},
+		&testNode{id: "w3", name: "Worker"},
+		&testNode{id: "g1", name: "Gateway"},
+	}
+	got := r.BatchEvaluate(nodes)
+
+	if p.matches != 2 {
+		t.Fatalf("expected one Match per name, got %d", p.matches)
+	}
+	if len(got) != len(nodes) {
+		t.Fatalf("expected an entry per node, got %v", got)
+	}
+	for _, n := range nodes {
+		if ds := got[n.ID()]; len(ds) != 1 || ds[0].PolicyID != "m" {
+			t.Fatalf("node %s: unexpected decisions %+v", n.ID(), ds)
+		}
+	}
+}
+
+func TestBatchEvaluateRecoversPanics(t *testing.T) {
+	r := policy.New()
+	r.Register(panickyPolicy("match", 1, true))
+	r.Register(panickyPolicy("check", 2, false))
+	r.Register(warnPolicy{id: "next", prio: 3})
+
+	out := r.BatchEvaluate([]policy.Node{&testNode{id: "a", name: "job"}, &testNode{id: "b", name: "job"}})
+	for _, id := range []string{"a", "b"} {
+		ds := out[id]
+		if got := decisionIDs(ds); !reflect.DeepEqual(got, []string{"match", "check", "next"}) {
+			t.Fatalf("%s: decisions = %v", id, got)
+		}
+		if !isPanicWarning(ds[0]) || !isPanicWarning(ds[1]) {
+			t.Fatalf("%s: panics not reported as warnings: %+v", id, ds)
+		}
+	}
+}
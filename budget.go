@@ -0,0 +1,170 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+import (
+	"context"
+	"time"
+)
+
+// ContextPolicy is an optional interface for policies whose Check can block
+// (remote lookups, I/O). When implemented, context-aware evaluation calls
+// CheckContext instead of Check and the policy is expected to return promptly
+// once ctx is done.
+type ContextPolicy interface {
+	CheckContext(ctx context.Context, n Node) []Decision
+}
+
+// NodeEvaluationBudget is an optional interface for nodes that carry their own
+// evaluation time budget, so that, e.g., critical-path nodes can be given
+// more time than background ones. Context-aware evaluation bounds the whole
+// evaluation of such a node by min(ctx's deadline, time.Now()+budget); a
+// budget <= 0 means no limit.
+type NodeEvaluationBudget interface {
+	EvaluationBudget() time.Duration
+}
+
+// withNodeBudget derives the evaluation context for n (see
+// NodeEvaluationBudget). The returned cancel function must always be called.
+func withNodeBudget(ctx context.Context, n Node) (context.Context, context.CancelFunc) {
+	if b, ok := n.(NodeEvaluationBudget); ok {
+		if d := b.EvaluationBudget(); d > 0 {
+			// WithTimeout already keeps the earlier of the two deadlines.
+			return context.WithTimeout(ctx, d)
+		}
+	}
+	return ctx, func() {}
+}
+
+// EvaluateWithBudget behaves like Evaluate but gives every matching policy its
+// own deadline: min(ctx's deadline, time.Now()+perPolicy), computed just before
+// the policy runs. A slow policy therefore cannot eat into the time of the
+// policies after it. perPolicy <= 0 means no per-policy limit.
+//
+// Notes:
+//   - Only ContextPolicy implementations observe the derived context; plain
+//     policies run to completion as with Evaluate.
+//   - If ctx is done before a policy runs, evaluation stops and the decisions
+//     collected so far are returned together with ctx.Err().
+//   - A policy that overruns its own budget does not abort evaluation; its
+//     decisions are kept as returned.
+//   - If n implements NodeEvaluationBudget, its budget also bounds ctx for
+//     the evaluation as a whole.
+//   - Panics in Match, Check or CheckContext are recovered as in Evaluate,
+//     and Hooks.OnStop is notified of a Stop. Middleware installed with Use
+//     is not applied.
+func (r *Registry) EvaluateWithBudget(ctx context.Context, n Node, perPolicy time.Duration) ([]Decision, error) {
+	ctx, cancel := withNodeBudget(ctx, n)
+	defer cancel()
+	var out []Decision
+	pols := r.snapshot()
+	hooks := r.currentHooks()
+	for i, p := range pols {
+		if err := ctx.Err(); err != nil {
+			return out, err
+		}
+		matched, err := safeMatch(p, n)
+		if err == nil && !matched {
+			continue
+		}
+		ds := r.checkOrWarn(p, err, func() []Decision { return checkWithBudget(ctx, p, n, perPolicy) })
+		for _, d := range ds {
+			out = append(out, d)
+			if d.Stop {
+				if hooks.OnStop != nil {
+					hooks.OnStop(d, pols[i+1:])
+				}
+				return out, nil
+			}
+		}
+	}
+	return out, nil
+}
+
+// EvaluateWithBudget runs Registry.EvaluateWithBudget on the global registry.
+func EvaluateWithBudget(ctx context.Context, n Node, perPolicy time.Duration) ([]Decision, error) {
+	return defaultRegistry.EvaluateWithBudget(ctx, n, perPolicy)
+}
+
+// EvaluateContext behaves like Evaluate, middleware, hooks and panic recovery
+// included, but checks ctx before each policy: once ctx is done, evaluation
+// stops and the Decisions collected so far are returned together with
+// ctx.Err(). ContextPolicy implementations receive ctx, and a node
+// implementing NodeEvaluationBudget further bounds it. Without middleware it
+// is EvaluateWithBudget with no per-policy limit; middleware that does not
+// call next yields its own Decisions and a nil error.
+func (r *Registry) EvaluateContext(ctx context.Context, n Node) ([]Decision, error) {
+	var err error
+	core := func(n Node) []Decision {
+		var ds []Decision
+		ds, err = r.EvaluateWithBudget(ctx, n, 0)
+		return ds
+	}
+	ds := chainMiddleware(r.currentMiddleware(), core)(n)
+	return ds, err
+}
+
+// EvaluateContext runs Registry.EvaluateContext on the global registry.
+func EvaluateContext(ctx context.Context, n Node) ([]Decision, error) {
+	return defaultRegistry.EvaluateContext(ctx, n)
+}
+
+// checkWithBudget runs p against n under a context bounded by perPolicy.
+func checkWithBudget(ctx context.Context, p Policy, n Node, perPolicy time.Duration) []Decision {
+	if _, ok := asPolicy[ContextPolicy](p); !ok {
+		return checkPolicy(p, n)
+	}
+	n = bindNode(n, p)
+	if perPolicy > 0 {
+		// WithTimeout already keeps the earlier of the two deadlines.
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, perPolicy)
+		defer cancel()
+	}
+	return checkWithContext(ctx, p, n)
+}
+
+// CheckPolicyContext runs p against n as context-aware evaluation does:
+// through CheckContext when p, or a policy it wraps, is a ContextPolicy, and
+// through Check otherwise. Policy wrappers use it to pass ctx on to the
+// policy they wrap (see WrappedPolicy).
+func CheckPolicyContext(ctx context.Context, p Policy, n Node) []Decision {
+	return checkWithContext(ctx, p, n)
+}
+
+// checkWithContext runs p against n through CheckContext when p's wrapper
+// chain holds a ContextPolicy, and through Check otherwise.
+func checkWithContext(ctx context.Context, p Policy, n Node) []Decision {
+	if ds, ok := checkContext(ctx, p, n); ok {
+		return ds
+	}
+	return p.Check(n)
+}
+
+// checkContext calls CheckContext on the first ContextPolicy in p's wrapper
+// chain, with n as that policy sees it, and passes the result back out
+// through the wrappers above it. ok is false if there is no ContextPolicy in
+// the chain.
+func checkContext(ctx context.Context, p Policy, n Node) (ds []Decision, ok bool) {
+	switch w := p.(type) {
+	case ContextPolicy:
+		return w.CheckContext(ctx, n), true
+	case policyWrapper:
+		if ds, ok = checkContext(ctx, w.unwrapPolicy(), w.wrapNode(n)); ok {
+			return w.wrapDecisions(ds), true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,190 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+// slowPolicy blocks until its context is done, then reports how much budget it
+// was given.
+type slowPolicy struct {
+	id   string
+	prio int
+	got  time.Duration
+}
+
+func (p *slowPolicy) ID() string                          { return p.id }
+func (p *slowPolicy) Priority() int                       { return p.prio }
+func (p *slowPolicy) Match(policy.Node) bool              { return true }
+func (p *slowPolicy) Check(policy.Node) []policy.Decision { return nil }
+func (p *slowPolicy) CheckContext(ctx context.Context, _ policy.Node) []policy.Decision {
+	start := time.Now()
+	<-ctx.Done()
+	p.got = time.Since(start)
+	return []policy.Decision{{PolicyID: p.id, Action: policy.ActionWarn}}
+}
+
+func TestEvaluateWithBudgetGivesEachPolicyItsOwnDeadline(t *testing.T) {
+	r := policy.New()
+	a := &slowPolicy{id: "a", prio: 1}
+	b := &slowPolicy{id: "b", prio: 2}
+	r.Register(a)
+	r.Register(b)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ds, err := r.EvaluateWithBudget(ctx, &testNode{id: "n"}, 20*time.Millisecond)
+	if err != nil {
+		t.Fatalf("EvaluateWithBudget: %v", err)
+	}
+	if len(ds) != 2 {
+		t.Fatalf("expected both policies to run, got %+v", ds)
+	}
+	if b.got < 15*time.Millisecond {
+		t.Fatalf("second policy was starved: %v", b.got)
+	}
+}
+
+func TestEvaluateWithBudgetStopsWhenParentDone(t *testing.T) {
+	r := policy.New()
+	r.Register(&slowPolicy{id: "a", prio: 1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	ds, err := r.EvaluateWithBudget(ctx, &testNode{id: "n"}, time.Second)
+	if !errors.Is(err, context.Canceled) || len(ds) != 0 {
+		t.Fatalf("expected no decisions and context.Canceled, got %+v, %v", ds, err)
+	}
+}
+
+// budgetNode carries its own evaluation budget.
+type budgetNode struct {
+	*testNode
+	budget time.Duration
+}
+
+func (n budgetNode) EvaluationBudget() time.Duration { return n.budget }
+
+func TestEvaluateWithBudgetHonoursNodeBudget(t *testing.T) {
+	r := policy.New()
+	a := &slowPolicy{id: "a", prio: 1}
+	r.Register(a)
+	r.Register(&slowPolicy{id: "b", prio: 2})
+
+	n := budgetNode{testNode: &testNode{id: "n"}, budget: 20 * time.Millisecond}
+	ds, err := r.EvaluateWithBudget(context.Background(), n, 0)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected node budget to expire, got %v", err)
+	}
+	if len(ds) != 1 || a.got > time.Second {
+		t.Fatalf("expected only the first policy within the node budget, got %+v after %v", ds, a.got)
+	}
+}
+
+func TestEvaluateContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r := policy.New()
+	r.Register(warnPolicy{id: "a", prio: 1})
+	r.Register(&policy.FuncPolicy{
+		PolicyID: "cancel",
+		Prio:     2,
+		MatchFn:  func(policy.Node) bool { return true },
+		CheckFn:  func(policy.Node) []policy.Decision { cancel(); return nil },
+	})
+	r.Register(warnPolicy{id: "b", prio: 3})
+
+	ds, err := r.EvaluateContext(ctx, &testNode{id: "n"})
+	if !errors.Is(err, context.Canceled) || len(ds) != 1 || ds[0].PolicyID != "a" {
+		t.Fatalf("got %v, %v; want [a] and context.Canceled", decisionIDs(ds), err)
+	}
+
+	ds, err = r.EvaluateContext(context.Background(), &testNode{id: "n"})
+	if err != nil || len(ds) != 2 {
+		t.Fatalf("uncancelled: %v, %v", decisionIDs(ds), err)
+	}
+}
+
+func TestEvaluateWithBudgetRecoversPanics(t *testing.T) {
+	r := policy.New()
+	r.Register(panickyPolicy("match", 1, true))
+	r.Register(panickyPolicy("check", 2, false))
+	r.Register(warnPolicy{id: "next", prio: 3})
+
+	for name, eval := range map[string]func(policy.Node) ([]policy.Decision, error){
+		"EvaluateContext": func(n policy.Node) ([]policy.Decision, error) {
+			return r.EvaluateContext(context.Background(), n)
+		},
+		"EvaluateWithBudget": func(n policy.Node) ([]policy.Decision, error) {
+			return r.EvaluateWithBudget(context.Background(), n, time.Second)
+		},
+	} {
+		ds, err := eval(&testNode{id: "n"})
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if got := decisionIDs(ds); !reflect.DeepEqual(got, []string{"match", "check", "next"}) {
+			t.Fatalf("%s: decisions = %v", name, got)
+		}
+		if !isPanicWarning(ds[0]) || !isPanicWarning(ds[1]) {
+			t.Fatalf("%s: panics not reported as warnings: %+v", name, ds)
+		}
+	}
+}
+
+func TestEvaluateContextMiddlewareAndHooks(t *testing.T) {
+	r := policy.New()
+	r.Register(&policy.FuncPolicy{
+		PolicyID: "stop",
+		MatchFn:  func(policy.Node) bool { return true },
+		CheckFn: func(policy.Node) []policy.Decision {
+			return []policy.Decision{{Action: policy.ActionCancelNode, Stop: true}}
+		},
+	})
+	r.Register(warnPolicy{id: "late", prio: 1})
+	var remaining []string
+	r.SetHooks(policy.Hooks{OnStop: func(_ policy.Decision, rest []policy.Policy) {
+		for _, p := range rest {
+			remaining = append(remaining, p.ID())
+		}
+	}})
+	r.Use(func(n policy.Node, next func(policy.Node) []policy.Decision) []policy.Decision {
+		return append(next(n), policy.Decision{PolicyID: "mw", Action: policy.ActionAudit})
+	})
+
+	ds, err := r.EvaluateContext(context.Background(), &testNode{id: "n"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := decisionIDs(ds); !reflect.DeepEqual(got, []string{"stop", "mw"}) {
+		t.Fatalf("decisions = %v", got)
+	}
+	if !reflect.DeepEqual(remaining, []string{"late"}) {
+		t.Fatalf("OnStop saw %v", remaining)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := r.EvaluateContext(ctx, &testNode{id: "n"}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err through middleware = %v", err)
+	}
+}
@@ -0,0 +1,379 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// This file contains ready-made Policy implementations for common rules.
+
+// paramRangePolicy keeps a numeric parameter within [min, max].
+type paramRangePolicy struct {
+	id       string
+	priority int
+	key      string
+	min, max float64
+	action   Action
+}
+
+// NewParamRangePolicy returns a Policy that checks n.Params()[paramKey] lies
+// within [min, max] and emits a single Decision with the given action when it
+// does not.
+//
+// Integer, unsigned, and floating-point values are accepted; nodes without the
+// key do not match, and non-numeric values are ignored. With ActionAdjust the
+// emitted Adjust clamps the value into range, preserving its original type
+// (an int stays an int). Other actions (e.g., ActionCancelNode) are emitted
+// as-is with a Reason describing the violation.
+func NewParamRangePolicy(id string, priority int, paramKey string, min, max float64, action Action) Policy {
+	return paramRangePolicy{id: id, priority: priority, key: paramKey, min: min, max: max, action: action}
+}
+
+func (p paramRangePolicy) ID() string    { return p.id }
+func (p paramRangePolicy) Priority() int { return p.priority }
+
+func (p paramRangePolicy) Match(n Node) bool {
+	_, ok := Param(n, p.key)
+	return ok
+}
+
+func (p paramRangePolicy) Check(n Node) []Decision {
+	raw, _ := Param(n, p.key)
+	v, ok := toFloat64(raw)
+	if !ok || (v >= p.min && v <= p.max) {
+		return nil
+	}
+	d := Decision{
+		PolicyID: p.id,
+		Scope:    ScopeNode,
+		Action:   p.action,
+		Reason:   fmt.Errorf("param %q = %v outside [%g, %g]", p.key, raw, p.min, p.max),
+	}
+	if p.action == ActionAdjust {
+		d.Adjust = func(params map[string]any) {
+			cur := params[p.key]
+			if f, ok := toFloat64(cur); ok {
+				params[p.key] = convertLike(cur, clamp(f, p.min, p.max))
+			}
+		}
+	}
+	return []Decision{d}
+}
+
+// toFloat64 converts any Go numeric value to float64.
+func toFloat64(v any) (float64, bool) {
+	switch x := v.(type) {
+	case int:
+		return float64(x), true
+	case int8:
+		return float64(x), true
+	case int16:
+		return float64(x), true
+	case int32:
+		return float64(x), true
+	case int64:
+		return float64(x), true
+	case uint:
+		return float64(x), true
+	case uint8:
+		return float64(x), true
+	case uint16:
+		return float64(x), true
+	case uint32:
+		return float64(x), true
+	case uint64:
+		return float64(x), true
+	case float32:
+		return float64(x), true
+	case float64:
+		return x, true
+	}
+	return 0, false
+}
+
+// convertLike converts f back to the numeric type of like.
+func convertLike(like any, f float64) any {
+	switch like.(type) {
+	case int:
+		return int(f)
+	case int8:
+		return int8(f)
+	case int16:
+		return int16(f)
+	case int32:
+		return int32(f)
+	case int64:
+		return int64(f)
+	case uint:
+		return uint(f)
+	case uint8:
+		return uint8(f)
+	case uint16:
+		return uint16(f)
+	case uint32:
+		return uint32(f)
+	case uint64:
+		return uint64(f)
+	case float32:
+		return float32(f)
+	}
+	return f
+}
+
+func clamp(v, min, max float64) float64 {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
+
+// mutualExclusionPolicy cancels a node running beneath a conflicting ancestor.
+type mutualExclusionPolicy struct {
+	id        string
+	priority  int
+	exclusive string
+}
+
+// NewMutualExclusionPolicy returns a Policy that cancels the current node
+// (ActionCancelNode) when any of its ancestors, found by following Parent(),
+// has Name() == exclusiveName. The node itself is not considered, so the
+// exclusive operation can still run; only work nested beneath it is rejected.
+func NewMutualExclusionPolicy(id string, priority int, exclusiveName string) Policy {
+	return mutualExclusionPolicy{id: id, priority: priority, exclusive: exclusiveName}
+}
+
+func (p mutualExclusionPolicy) ID() string    { return p.id }
+func (p mutualExclusionPolicy) Priority() int { return p.priority }
+
+func (p mutualExclusionPolicy) Match(n Node) bool { return p.conflict(n) != nil }
+
+func (p mutualExclusionPolicy) Check(n Node) []Decision {
+	a := p.conflict(n)
+	if a == nil {
+		return nil
+	}
+	return []Decision{{
+		PolicyID: p.id,
+		Scope:    ScopeNode,
+		Action:   ActionCancelNode,
+		Reason:   fmt.Errorf("ancestor %s is %q, which excludes %s", a.ID(), p.exclusive, n.ID()),
+	}}
+}
+
+// conflict returns the nearest ancestor of n named p.exclusive, or nil.
+func (p mutualExclusionPolicy) conflict(n Node) Node {
+	for a := n.Parent(); a != nil; a = a.Parent() {
+		if a.Name() == p.exclusive {
+			return a
+		}
+	}
+	return nil
+}
+
+// ErrThrottleExceeded is the Reason of decisions emitted by the policy from
+// NewThrottleWarnPolicy.
+var ErrThrottleExceeded = Reason("throttle threshold exceeded")
+
+// slidingWindow counts events per key over the trailing window. Keys without
+// events in the window are dropped, at most one window after their last
+// event, so memory is bounded by the keys active in the last two windows.
+type slidingWindow struct {
+	window time.Duration
+
+	mu    sync.Mutex
+	hits  map[string][]time.Time
+	swept time.Time // when stale keys were last dropped
+}
+
+func newSlidingWindow(window time.Duration) *slidingWindow {
+	return &slidingWindow{window: window, hits: map[string][]time.Time{}}
+}
+
+// add records an event for key at now and returns the number of events for
+// key within (now-window, now], including this one.
+func (w *slidingWindow) add(key string, now time.Time) int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ts := w.hits[key]
+	cutoff := now.Add(-w.window)
+	i := 0
+	for i < len(ts) && !ts[i].After(cutoff) {
+		i++
+	}
+	ts = append(ts[i:], now)
+	w.hits[key] = ts
+	if now.Sub(w.swept) >= w.window {
+		for k, ts := range w.hits {
+			if !ts[len(ts)-1].After(cutoff) {
+				delete(w.hits, k)
+			}
+		}
+		w.swept = now
+	}
+	return len(ts)
+}
+
+// reset forgets every key's event history.
+func (w *slidingWindow) reset() {
+	w.mu.Lock()
+	w.hits = map[string][]time.Time{}
+	w.mu.Unlock()
+}
+
+// throttleWarnPolicy warns about nodes evaluated too often.
+type throttleWarnPolicy struct {
+	id       string
+	priority int
+	max      int
+	hits     *slidingWindow
+}
+
+// NewThrottleWarnPolicy returns a Policy that matches every node and counts,
+// per Node.ID(), how often it is checked within the trailing window. Once the
+// count exceeds maxPerWindow, each further Check emits an ActionWarn with
+// ErrThrottleExceeded as Reason. Unlike a cancelling rate limit it never
+// blocks work, which suits a gradual rollout of rate awareness.
+//
+// Each Check counts as one event, so register the policy in a single
+// registry. Per-node state is kept until a node has gone a full window
+// without checks.
+func NewThrottleWarnPolicy(id string, priority int, maxPerWindow int, window time.Duration) Policy {
+	return &throttleWarnPolicy{id: id, priority: priority, max: maxPerWindow, hits: newSlidingWindow(window)}
+}
+
+func (p *throttleWarnPolicy) ID() string        { return p.id }
+func (p *throttleWarnPolicy) Priority() int     { return p.priority }
+func (p *throttleWarnPolicy) Match(n Node) bool { return true }
+
+func (p *throttleWarnPolicy) Check(n Node) []Decision {
+	if p.hits.add(n.ID(), time.Now()) <= p.max {
+		return nil
+	}
+	return []Decision{{
+		PolicyID: p.id,
+		Scope:    ScopeNode,
+		Action:   ActionWarn,
+		Reason:   ErrThrottleExceeded,
+	}}
+}
+
+// Reset implements Resettable by forgetting all check counts.
+func (p *throttleWarnPolicy) Reset() { p.hits.reset() }
+
+// ErrInvalidEscalation is the panic value of NewEscalatingPolicy when
+// cancelThreshold does not exceed warnThreshold.
+var ErrInvalidEscalation = errors.New("ccxpolicy: escalation cancelThreshold must exceed warnThreshold")
+
+// escalatingPolicy hardens repeated warnings from the wrapped policy.
+type escalatingPolicy struct {
+	Policy
+	warnThreshold, cancelThreshold int
+	warns                          *slidingWindow
+}
+
+// NewEscalatingPolicy wraps inner so that repeated warnings about the same
+// node turn into cancellations. Every ActionWarn that inner emits for a node
+// is counted per Node.ID() over the trailing window; with count including the
+// current warning:
+//
+//   - count <= warnThreshold: the warning is passed through as-is;
+//   - count > warnThreshold: it becomes ActionCancelNode (ScopeNode);
+//   - count > cancelThreshold: it becomes ActionCancelSubtree (ScopeSubtree).
+//
+// Escalated decisions keep their PolicyID and wrap the original Reason, if
+// any. Decisions with other actions pass through unchanged and are not
+// counted; the slice inner returned is never modified. ID, Priority, and
+// Match are delegated to inner, and inner's optional interfaces
+// (PrecisePriority, Explainable, ContextPolicy, ...) stay in effect. A node's
+// count is forgotten once it has gone a full window without warnings.
+//
+// Like Register, NewEscalatingPolicy panics on a configuration error: it
+// panics with ErrInvalidEscalation unless cancelThreshold > warnThreshold.
+func NewEscalatingPolicy(inner Policy, warnThreshold, cancelThreshold int, window time.Duration) Policy {
+	if cancelThreshold <= warnThreshold {
+		panic(fmt.Errorf("%w (warn %d, cancel %d)", ErrInvalidEscalation, warnThreshold, cancelThreshold))
+	}
+	return &escalatingPolicy{
+		Policy:          inner,
+		warnThreshold:   warnThreshold,
+		cancelThreshold: cancelThreshold,
+		warns:           newSlidingWindow(window),
+	}
+}
+
+func (p *escalatingPolicy) Check(n Node) []Decision {
+	return p.escalate(n, p.Policy.Check(n))
+}
+
+// CheckContext is Check for context-aware evaluation.
+func (p *escalatingPolicy) CheckContext(ctx context.Context, n Node) []Decision {
+	return p.escalate(n, checkWithContext(ctx, p.Policy, n))
+}
+
+// escalate returns a copy of ds, the inner policy's Decisions for n, with
+// repeated warnings escalated.
+func (p *escalatingPolicy) escalate(n Node, ds []Decision) []Decision {
+	ds = slices.Clone(ds)
+	now := time.Now()
+	for i, d := range ds {
+		if d.Action != ActionWarn {
+			continue
+		}
+		count := p.warns.add(n.ID(), now)
+		switch {
+		case count > p.cancelThreshold:
+			ds[i].Action, ds[i].Scope = ActionCancelSubtree, ScopeSubtree
+		case count > p.warnThreshold:
+			ds[i].Action, ds[i].Scope = ActionCancelNode, ScopeNode
+		default:
+			continue
+		}
+		if d.Reason != nil {
+			ds[i].Reason = fmt.Errorf("escalated after %d warnings within %s: %w", count, p.warns.window, d.Reason)
+		} else {
+			ds[i].Reason = fmt.Errorf("escalated after %d warnings within %s", count, p.warns.window)
+		}
+	}
+	return ds
+}
+
+func (p *escalatingPolicy) unwrapPolicy() Policy                   { return p.Policy }
+func (p *escalatingPolicy) wrapDecisions(ds []Decision) []Decision { return ds }
+func (p *escalatingPolicy) wrapNode(n Node) Node                   { return n }
+
+// rewrapPolicy returns an escalating wrapper around inner that shares p's
+// warning counts.
+func (p *escalatingPolicy) rewrapPolicy(inner Policy) Policy {
+	q := *p
+	q.Policy = inner
+	return &q
+}
+
+// Reset implements Resettable by forgetting all counted warnings and
+// resetting the wrapped policy.
+func (p *escalatingPolicy) Reset() {
+	p.warns.reset()
+	resetPolicy(p.Policy)
+}
@@ -0,0 +1,177 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+func TestParamRangePolicy(t *testing.T) {
+	p := policy.NewParamRangePolicy("range", 1, "q", 240, 1080, policy.ActionAdjust)
+
+	cases := []struct {
+		val  any
+		want any // nil means no decision
+	}{
+		{1440, 1080},
+		{int64(100), int64(240)},
+		{float32(2000.5), float32(1080)},
+		{720.0, nil},
+		{uint(1080), nil},
+		{"1440", nil},
+	}
+	for _, c := range cases {
+		n := &testNode{params: map[string]any{"q": c.val}}
+		if !p.Match(n) {
+			t.Fatalf("%v: expected Match", c.val)
+		}
+		ds := p.Check(n)
+		if c.want == nil {
+			if len(ds) != 0 {
+				t.Fatalf("%v: unexpected decisions %+v", c.val, ds)
+			}
+			continue
+		}
+		if len(ds) != 1 || ds[0].Action != policy.ActionAdjust || ds[0].PolicyID != "range" {
+			t.Fatalf("%v: decisions %+v", c.val, ds)
+		}
+		ds[0].Adjust(n.params)
+		if n.params["q"] != c.want {
+			t.Fatalf("%v: clamped to %#v, want %#v", c.val, n.params["q"], c.want)
+		}
+	}
+
+	if p.Match(&testNode{params: map[string]any{}}) {
+		t.Fatalf("missing key should not match")
+	}
+
+	cancel := policy.NewParamRangePolicy("range.cancel", 1, "q", 0, 10, policy.ActionCancelNode)
+	ds := cancel.Check(&testNode{params: map[string]any{"q": 11}})
+	if len(ds) != 1 || ds[0].Action != policy.ActionCancelNode || ds[0].Adjust != nil {
+		t.Fatalf("cancel decisions %+v", ds)
+	}
+}
+
+func TestMutualExclusionPolicy(t *testing.T) {
+	p := policy.NewMutualExclusionPolicy("mutex", 1, "Migrate")
+
+	root := &testNode{id: "r", name: "Pipeline"}
+	migrate := &testNode{id: "m", name: "Migrate", parent: root}
+	backup := &testNode{id: "b", name: "Backup", parent: migrate}
+	sibling := &testNode{id: "s", name: "Backup", parent: root}
+
+	if p.Match(migrate) || p.Match(sibling) || p.Match(root) {
+		t.Fatalf("nodes without a Migrate ancestor must not match")
+	}
+	if !p.Match(backup) {
+		t.Fatalf("node beneath Migrate should match")
+	}
+	ds := p.Check(backup)
+	if len(ds) != 1 || ds[0].Action != policy.ActionCancelNode || ds[0].Scope != policy.ScopeNode {
+		t.Fatalf("decisions %+v", ds)
+	}
+	if got := ds[0].Reason.Error(); got != `ancestor m is "Migrate", which excludes b` {
+		t.Fatalf("reason = %q", got)
+	}
+}
+
+// ExampleNewParamRangePolicy clamps an out-of-range quality setting.
+func ExampleNewParamRangePolicy() {
+	p := policy.NewParamRangePolicy("cap_quality", 10, "quality", 240, 1080, policy.ActionAdjust)
+	n := &testNode{id: "n1", name: "Transcode", params: map[string]any{"quality": 1440}}
+
+	for _, d := range p.Check(n) {
+		fmt.Println(d.Reason)
+		d.Adjust(n.params)
+	}
+	fmt.Println(n.params["quality"])
+	// Output:
+	// param "quality" = 1440 outside [240, 1080]
+	// 1080
+}
+
+func TestThrottleWarnPolicy(t *testing.T) {
+	p := policy.NewThrottleWarnPolicy("throttle", 1, 2, 50*time.Millisecond)
+	a := &testNode{id: "a"}
+	b := &testNode{id: "b"}
+
+	for i := 0; i < 2; i++ {
+		if ds := p.Check(a); len(ds) != 0 {
+			t.Fatalf("call %d within threshold warned: %+v", i+1, ds)
+		}
+	}
+	ds := p.Check(a)
+	if len(ds) != 1 || ds[0].Action != policy.ActionWarn || !errors.Is(ds[0].Reason, policy.ErrThrottleExceeded) {
+		t.Fatalf("expected throttle warning, got %+v", ds)
+	}
+	if ds := p.Check(b); len(ds) != 0 {
+		t.Fatalf("counts leaked between nodes: %+v", ds)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if ds := p.Check(a); len(ds) != 0 {
+		t.Fatalf("window did not slide: %+v", ds)
+	}
+}
+
+func TestEscalatingPolicy(t *testing.T) {
+	p := policy.NewEscalatingPolicy(warnPolicy{id: "noisy"}, 1, 2, time.Minute)
+	n := &testNode{id: "n"}
+
+	var got []policy.Action
+	for i := 0; i < 4; i++ {
+		ds := p.Check(n)
+		if len(ds) != 1 {
+			t.Fatalf("call %d: %+v", i+1, ds)
+		}
+		got = append(got, ds[0].Action)
+	}
+	want := []policy.Action{policy.ActionWarn, policy.ActionCancelNode, policy.ActionCancelSubtree, policy.ActionCancelSubtree}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("actions = %v, want %v", got, want)
+	}
+	if p.ID() != "noisy" {
+		t.Fatalf("ID not delegated: %q", p.ID())
+	}
+}
+
+func TestEscalatingPolicyLeavesInnerDecisionsAndNilReasons(t *testing.T) {
+	inner := &sharedPolicy{id: "shared", ds: []policy.Decision{{PolicyID: "shared", Action: policy.ActionWarn}}}
+	p := policy.NewEscalatingPolicy(inner, 0, 5, time.Minute)
+
+	ds := p.Check(&testNode{id: "n"})
+	if len(ds) != 1 || ds[0].Action != policy.ActionCancelNode {
+		t.Fatalf("decisions = %+v", ds)
+	}
+	if msg := ds[0].Reason.Error(); strings.Contains(msg, "%!") || errors.Unwrap(ds[0].Reason) != nil {
+		t.Fatalf("reason = %q", msg)
+	}
+	if inner.ds[0].Action != policy.ActionWarn || inner.ds[0].Reason != nil {
+		t.Fatalf("inner decisions modified: %+v", inner.ds)
+	}
+
+	defer func() {
+		if err, _ := recover().(error); !errors.Is(err, policy.ErrInvalidEscalation) {
+			t.Fatalf("recovered %v, want ErrInvalidEscalation", err)
+		}
+	}()
+	policy.NewEscalatingPolicy(inner, 3, 3, time.Minute)
+}
@@ -0,0 +1,265 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+)
+
+// cacheKey identifies a cached evaluation: the node identity plus a hash of the
+// parameters it had at evaluation time. A change in either yields a new key.
+type cacheKey struct {
+	nodeID    string
+	paramHash uint64
+}
+
+// cacheEntry is the cached evaluation of a node. The cache holds one entry
+// per node ID, so a node whose params change replaces its entry rather than
+// adding one per param hash.
+type cacheEntry struct {
+	paramHash uint64
+	ds        []Decision
+}
+
+// ParamHashNode returns n together with a deterministic hash of n.Params().
+//
+// The hash covers every key and the type and formatted value of every entry,
+// visited in sorted key order, so two maps with equal contents always hash
+// the same regardless of insertion order. A nil node hashes to zero.
+func ParamHashNode(n Node) (Node, uint64) {
+	if n == nil {
+		return nil, 0
+	}
+	return n, hashParams(n.Params())
+}
+
+// hashParams computes an FNV-1a hash of params in sorted key order.
+func hashParams(params map[string]any) uint64 {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h := fnv.New64a()
+	for _, k := range keys {
+		fmt.Fprintf(h, "%q=%T:%v;", k, params[k], params[k])
+	}
+	return h.Sum64()
+}
+
+// CachedEvaluate behaves like Evaluate but memoizes the result per
+// (n.ID(), param hash). A subsequent call for the same node ID with unchanged
+// Params returns the cached Decisions without running any policy.
+//
+// Notes:
+//   - Changing any parameter value invalidates the entry implicitly, since the
+//     hash becomes part of the key. Only the latest result per node ID is
+//     kept, so the cache holds at most one entry per node.
+//   - Registering a policy clears the whole cache. A result computed while
+//     the cache was being cleared is returned but not cached.
+//   - The returned slice is a copy; callers may modify it freely.
+func (r *Registry) CachedEvaluate(n Node) []Decision {
+	_, h := ParamHashNode(n)
+	key := cacheKey{nodeID: n.ID(), paramHash: h}
+
+	ds, gen, ok := r.loadCached(key)
+	if ok {
+		return append([]Decision(nil), ds...)
+	}
+	ds = r.Evaluate(n)
+	r.storeCached(key, ds, gen)
+	return append([]Decision(nil), ds...)
+}
+
+// loadCached returns the cached Decisions for key, if any, and the cache
+// generation to pass to storeCached after evaluating on a miss.
+func (r *Registry) loadCached(key cacheKey) (ds []Decision, gen uint64, ok bool) {
+	r.cacheMu.Lock()
+	defer r.cacheMu.Unlock()
+	e, ok := r.cache[key.nodeID]
+	if !ok || e.paramHash != key.paramHash {
+		return nil, r.cacheGen, false
+	}
+	return e.ds, r.cacheGen, true
+}
+
+// storeCached caches ds under key, replacing any entry for the same node ID,
+// unless the cache was invalidated since gen was read: ds may then come from
+// a policy set that is no longer current.
+func (r *Registry) storeCached(key cacheKey, ds []Decision, gen uint64) {
+	r.cacheMu.Lock()
+	defer r.cacheMu.Unlock()
+	if r.cacheGen == gen {
+		r.cache[key.nodeID] = cacheEntry{paramHash: key.paramHash, ds: ds}
+	}
+}
+
+// CachedEvaluate runs Registry.CachedEvaluate on the global registry.
+func CachedEvaluate(n Node) []Decision { return defaultRegistry.CachedEvaluate(n) }
+
+// InvalidateCacheForNode evicts every cached evaluation for nodeID, whatever
+// parameter hash it was stored under.
+func (r *Registry) InvalidateCacheForNode(nodeID string) {
+	r.cacheMu.Lock()
+	defer r.cacheMu.Unlock()
+	r.cacheGen++
+	delete(r.cache, nodeID)
+}
+
+// InvalidateCacheForNode evicts nodeID from the global registry's cache.
+func InvalidateCacheForNode(nodeID string) { defaultRegistry.InvalidateCacheForNode(nodeID) }
+
+// invalidateCache drops every cached evaluation in r and in any registry
+// layered on top of it.
+func (r *Registry) invalidateCache() {
+	r.cacheMu.Lock()
+	r.cache = map[string]cacheEntry{}
+	r.cacheGen++
+	r.cacheMu.Unlock()
+
+	r.mu.RLock()
+	children := append([]*Registry(nil), r.children...)
+	r.mu.RUnlock()
+	for _, c := range children {
+		c.invalidateCache()
+	}
+}
+
+// memoizedPolicy wraps a Policy and shares Check results between nodes that
+// map to the same key. ID, Priority, and Match are delegated unchanged, and
+// the wrapped policy's optional interfaces stay visible (see policyWrapper).
+type memoizedPolicy struct {
+	Policy
+	keyFn func(Node) string
+
+	mu    sync.Mutex
+	cache map[string][]Decision
+}
+
+// MemoizedPolicy returns a Policy that calls p.Check at most once per key, as
+// computed by keyFn, and serves every later node with the same key from the
+// cached result. It is meant for fleets of interchangeable nodes, where e.g.
+//
+//	func(n Node) string {
+//		_, h := ParamHashNode(n)
+//		return fmt.Sprintf("%s#%x", n.Name(), h)
+//	}
+//
+// identifies nodes that would receive identical Decisions.
+//
+// Notes:
+//   - keyFn must capture everything Check depends on; nodes sharing a key get
+//     the same Decisions, including the same Adjust functions.
+//   - The cache is unbounded and lives as long as the returned Policy.
+//   - Returned slices are copies; callers may modify them freely.
+//   - The wrapped policy's optional interfaces (PrecisePriority, Explainable,
+//     ContextPolicy, ...) stay in effect; a ContextPolicy receives the
+//     evaluation context on a miss.
+func MemoizedPolicy(p Policy, keyFn func(Node) string) Policy {
+	return &memoizedPolicy{Policy: p, keyFn: keyFn, cache: map[string][]Decision{}}
+}
+
+// Check returns the cached Decisions for n's key, running the wrapped policy
+// on a miss.
+func (p *memoizedPolicy) Check(n Node) []Decision {
+	return p.memo(n, func() ([]Decision, bool) { return p.Policy.Check(n), true })
+}
+
+// CheckContext is Check for context-aware evaluation: a miss runs the wrapped
+// policy with ctx when it is a ContextPolicy. A result computed after ctx was
+// done is returned but not cached.
+func (p *memoizedPolicy) CheckContext(ctx context.Context, n Node) []Decision {
+	return p.memo(n, func() ([]Decision, bool) {
+		ds := checkWithContext(ctx, p.Policy, n)
+		return ds, ctx.Err() == nil
+	})
+}
+
+// memo returns a copy of the cached Decisions for n's key, calling check on a
+// miss and caching its result unless check reports otherwise.
+func (p *memoizedPolicy) memo(n Node, check func() (ds []Decision, cache bool)) []Decision {
+	key := p.keyFn(n)
+
+	p.mu.Lock()
+	ds, ok := p.cache[key]
+	p.mu.Unlock()
+	if !ok {
+		var cache bool
+		if ds, cache = check(); cache {
+			p.mu.Lock()
+			p.cache[key] = ds
+			p.mu.Unlock()
+		}
+	}
+	return append([]Decision(nil), ds...)
+}
+
+func (p *memoizedPolicy) unwrapPolicy() Policy                   { return p.Policy }
+func (p *memoizedPolicy) wrapDecisions(ds []Decision) []Decision { return ds }
+func (p *memoizedPolicy) wrapNode(n Node) Node                   { return n }
+
+// rewrapPolicy returns a memoizing wrapper around inner with an empty cache,
+// since the cached Decisions came from the old chain.
+func (p *memoizedPolicy) rewrapPolicy(inner Policy) Policy {
+	return MemoizedPolicy(inner, p.keyFn)
+}
+
+// Reset implements Resettable by dropping the cache and resetting the
+// wrapped policy.
+func (p *memoizedPolicy) Reset() {
+	p.mu.Lock()
+	p.cache = map[string][]Decision{}
+	p.mu.Unlock()
+	resetPolicy(p.Policy)
+}
+
+// NodeParamsHash returns the deterministic hash of n.Params() used as the
+// cache version (see ParamHashNode). A nil node hashes to zero.
+func NodeParamsHash(n Node) uint64 {
+	_, h := ParamHashNode(n)
+	return h
+}
+
+// VersionedEvaluate lets a caller skip re-evaluating a node whose params have
+// not changed. version is the value returned by the previous call for n (zero
+// the first time). It returns n's decisions, its current version
+// (NodeParamsHash(n)), and whether evaluation was skipped.
+//
+// Evaluation is skipped when version equals the current hash and the
+// registry still caches the result for it (see CachedEvaluate); registering a
+// policy clears the cache, so the next call re-evaluates even if the params
+// are unchanged. Fresh results are cached under the new version.
+func (r *Registry) VersionedEvaluate(n Node, version uint64) ([]Decision, uint64, bool) {
+	h := NodeParamsHash(n)
+	key := cacheKey{nodeID: n.ID(), paramHash: h}
+
+	ds, gen, ok := r.loadCached(key)
+	if ok && h == version {
+		return append([]Decision(nil), ds...), h, true
+	}
+	ds = r.Evaluate(n)
+	r.storeCached(key, ds, gen)
+	return append([]Decision(nil), ds...), h, false
+}
+
+// VersionedEvaluate runs Registry.VersionedEvaluate on the global registry.
+func VersionedEvaluate(n Node, version uint64) ([]Decision, uint64, bool) {
+	return defaultRegistry.VersionedEvaluate(n, version)
+}
@@ -0,0 +1,178 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"fmt"
+	"testing"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+// countingPolicy matches nodes by name and counts how often Check runs.
+type countingPolicy struct {
+	id, name string
+	checks   int
+}
+
+func (p *countingPolicy) ID() string               { return p.id }
+func (p *countingPolicy) Priority() int            { return 1 }
+func (p *countingPolicy) Match(n policy.Node) bool { return n.Name() == p.name }
+func (p *countingPolicy) Check(policy.Node) []policy.Decision {
+	p.checks++
+	return []policy.Decision{{PolicyID: p.id, Action: policy.ActionWarn}}
+}
+
+func TestParamHashNodeIsOrderIndependent(t *testing.T) {
+	a := map[string]any{}
+	a["x"], a["y"] = 1, "two"
+	b := map[string]any{}
+	b["y"], b["x"] = "two", 1
+
+	_, ha := policy.ParamHashNode(&testNode{id: "a", params: a})
+	_, hb := policy.ParamHashNode(&testNode{id: "b", params: b})
+	if ha != hb {
+		t.Fatalf("equal params hashed differently: %d vs %d", ha, hb)
+	}
+
+	b["x"] = 2
+	_, hc := policy.ParamHashNode(&testNode{id: "b", params: b})
+	if hc == ha {
+		t.Fatalf("changed params kept the same hash")
+	}
+}
+
+func TestCachedEvaluateInvalidation(t *testing.T) {
+	p := &countingPolicy{id: "cache.count", name: "cache-test"}
+	policy.RegisterPolicy(p)
+
+	n := &testNode{id: "c1", name: "cache-test", params: map[string]any{"q": 1}}
+
+	policy.CachedEvaluate(n)
+	policy.CachedEvaluate(n)
+	if p.checks != 1 {
+		t.Fatalf("expected 1 check with unchanged params, got %d", p.checks)
+	}
+
+	n.params["q"] = 2
+	policy.CachedEvaluate(n)
+	if p.checks != 2 {
+		t.Fatalf("expected param change to re-evaluate, got %d checks", p.checks)
+	}
+
+	policy.InvalidateCacheForNode("c1")
+	ds := policy.CachedEvaluate(n)
+	if p.checks != 3 {
+		t.Fatalf("expected explicit invalidation to re-evaluate, got %d checks", p.checks)
+	}
+	if len(ds) != 1 || ds[0].PolicyID != "cache.count" {
+		t.Fatalf("unexpected decisions %+v", ds)
+	}
+}
+
+func TestMemoizedPolicySharesResultsByKey(t *testing.T) {
+	inner := &countingPolicy{id: "memo", name: "worker"}
+	p := policy.MemoizedPolicy(inner, func(n policy.Node) string {
+		_, h := policy.ParamHashNode(n)
+		return fmt.Sprintf("%s#%x", n.Name(), h)
+	})
+	r := policy.New()
+	r.Register(p)
+
+	for i := 0; i < 3; i++ {
+		n := &testNode{id: fmt.Sprint("w", i), name: "worker", params: map[string]any{"q": 1}}
+		if ds := r.Evaluate(n); len(ds) != 1 || ds[0].PolicyID != "memo" {
+			t.Fatalf("unexpected decisions %+v", ds)
+		}
+	}
+	if inner.checks != 1 {
+		t.Fatalf("expected a single Check for identical nodes, got %d", inner.checks)
+	}
+
+	r.Evaluate(&testNode{id: "w9", name: "worker", params: map[string]any{"q": 2}})
+	if inner.checks != 2 {
+		t.Fatalf("expected a new key to run Check, got %d checks", inner.checks)
+	}
+}
+
+func TestVersionedEvaluate(t *testing.T) {
+	p := &countingPolicy{id: "versioned", name: "job"}
+	r := policy.New()
+	r.Register(p)
+	n := &testNode{id: "v1", name: "job", params: map[string]any{"q": 1}}
+
+	_, v, skipped := r.VersionedEvaluate(n, 0)
+	if skipped || v != policy.NodeParamsHash(n) {
+		t.Fatalf("first call: version=%d skipped=%v", v, skipped)
+	}
+	ds, v2, skipped := r.VersionedEvaluate(n, v)
+	if !skipped || v2 != v || len(ds) != 1 || p.checks != 1 {
+		t.Fatalf("unchanged params: skipped=%v version=%d ds=%+v checks=%d", skipped, v2, ds, p.checks)
+	}
+
+	n.params["q"] = 2
+	if _, v3, skipped := r.VersionedEvaluate(n, v); skipped || v3 == v || p.checks != 2 {
+		t.Fatalf("changed params: skipped=%v version=%d checks=%d", skipped, v3, p.checks)
+	}
+}
+
+func TestCacheSkipsResultsFromInvalidatedPolicySets(t *testing.T) {
+	for name, eval := range map[string]func(*policy.Registry, policy.Node) []policy.Decision{
+		"CachedEvaluate": func(r *policy.Registry, n policy.Node) []policy.Decision { return r.CachedEvaluate(n) },
+		"VersionedEvaluate": func(r *policy.Registry, n policy.Node) []policy.Decision {
+			ds, _, _ := r.VersionedEvaluate(n, policy.NodeParamsHash(n))
+			return ds
+		},
+	} {
+		r := policy.New()
+		registered := false
+		r.Register(&policy.FuncPolicy{
+			PolicyID: "registers",
+			MatchFn:  func(policy.Node) bool { return true },
+			CheckFn: func(policy.Node) []policy.Decision {
+				if !registered {
+					// A concurrent registration landing mid-evaluation.
+					registered = true
+					r.Register(warnPolicy{id: "late", prio: 1})
+				}
+				return nil
+			},
+		})
+		n := &testNode{id: "n"}
+		if ds := eval(r, n); len(ds) != 0 {
+			t.Fatalf("%s: first evaluation %+v", name, ds)
+		}
+		if ds := eval(r, n); len(ds) != 1 || ds[0].PolicyID != "late" {
+			t.Fatalf("%s: stale result cached: %+v", name, ds)
+		}
+	}
+}
+
+func TestCachedEvaluateKeepsOneEntryPerNode(t *testing.T) {
+	r := policy.New()
+	p := &countingPolicy{id: "count", name: "n"}
+	r.Register(p)
+	n := &testNode{id: "n1", name: "n", params: map[string]any{"q": 1}}
+
+	for _, q := range []int{1, 2, 1, 1} {
+		n.params["q"] = q
+		r.CachedEvaluate(n)
+	}
+	// The entry for q=1 was replaced by q=2, so returning to q=1 evaluates
+	// again; the final call is then a hit.
+	if p.checks != 3 {
+		t.Fatalf("checks = %d, want 3", p.checks)
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package policycel lets policy conditions be written as CEL expressions
+// (https://cel.dev) instead of Go code, so non-Go authors can express rich
+// conditions while evaluation stays sandboxed: expressions cannot perform I/O
+// and their cost is bounded. It lives in its own module so that the core
+// package stays free of third-party dependencies:
+//
+//	import policycel "github.com/ArieDeha/ccxpolicy/cel"
+//
+// Expressions see a single variable, node, a map with the keys
+//
+//   - id, name: Node.ID() and Node.Name()
+//   - params:   Node.Params() (an empty map when nil)
+//   - depth:    number of ancestors (the root has depth 0)
+//   - ancestors: a list of {id, name, params} maps, parent first
+//
+// Example:
+//
+//	node.name.startsWith("Transcode") &&
+//	    has(node.params.quality) && node.params.quality > 1080 &&
+//	    node.ancestors.exists(a, a.name == "Batch")
+//
+// Ints and doubles compare with each other, so params decoded from JSON
+// (float64) can be compared with integer literals.
+package policycel
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/google/cel-go/cel"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+// ErrInvalidExpr is wrapped by the errors returned for expressions that do
+// not compile or do not yield a bool.
+var ErrInvalidExpr = errors.New("policycel: invalid CEL expression")
+
+// DefaultCostLimit caps the runtime cost of one expression evaluation; an
+// evaluation exceeding it fails and the condition is treated as false.
+const DefaultCostLimit = 100_000
+
+var env = func() *cel.Env {
+	e, err := cel.NewEnv(
+		cel.Variable("node", cel.MapType(cel.StringType, cel.DynType)),
+		cel.CrossTypeNumericComparisons(true),
+	)
+	if err != nil {
+		panic(err) // static configuration
+	}
+	return e
+}()
+
+// Compile compiles a boolean CEL expression over node into a MatchFunc. The
+// MatchFunc reports false when evaluation fails at runtime (e.g. a missing
+// param accessed without has(), or the cost limit is exceeded) or yields a
+// non-bool. It returns an error wrapping ErrInvalidExpr if expr does not
+// compile or its static type is neither bool nor dyn (values read from
+// node are dyn, so e.g. node.params.enabled is accepted).
+func Compile(expr string) (policy.MatchFunc, error) {
+	ast, iss := env.Compile(expr)
+	if iss.Err() != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidExpr, iss.Err())
+	}
+	if !ast.OutputType().IsExactType(cel.BoolType) && !ast.OutputType().IsExactType(cel.DynType) {
+		return nil, fmt.Errorf("%w: %q has type %s, want bool", ErrInvalidExpr, expr, ast.OutputType())
+	}
+	prg, err := env.Program(ast, cel.CostLimit(DefaultCostLimit))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidExpr, err)
+	}
+	return func(n policy.Node) bool {
+		out, _, err := prg.Eval(map[string]any{"node": activation(n)})
+		if err != nil {
+			return false
+		}
+		b, ok := out.Value().(bool)
+		return ok && b
+	}, nil
+}
+
+// NewPolicy returns a Policy whose Match is the CEL expression match and
+// whose Check returns d, with PolicyID set to id, for nodes on which the CEL
+// expression check holds (and nothing otherwise). An empty match or check
+// expression is always true. Each returned Decision has its own copy of
+// d's Patch and Metadata. Errors wrap ErrInvalidExpr.
+func NewPolicy(id string, priority int, match, check string, d policy.Decision) (policy.Policy, error) {
+	m, err := compileOptional(match)
+	if err != nil {
+		return nil, fmt.Errorf("match: %w", err)
+	}
+	c, err := compileOptional(check)
+	if err != nil {
+		return nil, fmt.Errorf("check: %w", err)
+	}
+	d.PolicyID = id
+	return policy.PolicyFunc(id, priority, m, func(n policy.Node) []policy.Decision {
+		if !c(n) {
+			return nil
+		}
+		d := d
+		d.Patch = slices.Clone(d.Patch)
+		d.Metadata = maps.Clone(d.Metadata)
+		return []policy.Decision{d}
+	})
+}
+
+// compileOptional is Compile, treating an empty expression as true.
+func compileOptional(expr string) (policy.MatchFunc, error) {
+	if expr == "" {
+		return func(policy.Node) bool { return true }, nil
+	}
+	return Compile(expr)
+}
+
+// activation returns the value bound to the node variable for n.
+func activation(n policy.Node) map[string]any {
+	v := nodeValue(n)
+	var ancestors []any
+	for p := n.Parent(); p != nil; p = p.Parent() {
+		ancestors = append(ancestors, nodeValue(p))
+	}
+	if ancestors == nil {
+		ancestors = []any{}
+	}
+	v["depth"] = len(ancestors)
+	v["ancestors"] = ancestors
+	return v
+}
+
+// nodeValue returns the id, name, and params of n as a CEL-friendly map.
+func nodeValue(n policy.Node) map[string]any {
+	params := n.Params()
+	if params == nil {
+		params = map[string]any{}
+	}
+	return map[string]any{"id": n.ID(), "name": n.Name(), "params": params}
+}
@@ -0,0 +1,96 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policycel_test
+
+import (
+	"errors"
+	"testing"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+	policycel "github.com/ArieDeha/ccxpolicy/cel"
+)
+
+func TestCompile(t *testing.T) {
+	leaf := policy.NewMapNode("leaf", "TranscodeHD", map[string]any{"quality": 2160.0, "codec": "av1"})
+	policy.NewMapNode("root", "Batch", nil, leaf)
+
+	for expr, want := range map[string]bool{
+		`node.name.startsWith("Transcode")`:                      true,
+		`node.params.quality > 1080`:                             true, // double vs int
+		`node.params.codec in ["h264", "hevc"]`:                  false,
+		`node.depth == 1 && node.ancestors[0].name == "Batch"`:   true,
+		`node.ancestors.exists(a, a.id == "missing")`:            false,
+		`node.params.missing == 1`:                               false, // runtime error
+		`has(node.params.missing) || node.params.codec == "av1"`: true,
+	} {
+		m, err := policycel.Compile(expr)
+		if err != nil {
+			t.Fatalf("%s: %v", expr, err)
+		}
+		if got := m(leaf); got != want {
+			t.Errorf("%s = %v, want %v", expr, got, want)
+		}
+	}
+
+	for _, bad := range []string{`node.name +`, `1 + 2`, `unknown == 1`} {
+		if _, err := policycel.Compile(bad); !errors.Is(err, policycel.ErrInvalidExpr) {
+			t.Errorf("%s: got %v, want ErrInvalidExpr", bad, err)
+		}
+	}
+}
+
+func TestNewPolicy(t *testing.T) {
+	p, err := policycel.NewPolicy("cap", 300,
+		`node.name == "Transcode"`,
+		`node.params.quality > 1080`,
+		policy.Decision{Action: policy.ActionWarn, Reason: policy.Reason("quality above cap")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := policy.New()
+	r.Register(p)
+
+	ds := r.Evaluate(policy.NewMapNode("a", "Transcode", map[string]any{"quality": 2160}))
+	if len(ds) != 1 || ds[0].PolicyID != "cap" || ds[0].Action != policy.ActionWarn {
+		t.Fatalf("decisions = %+v", ds)
+	}
+	if ds := r.Evaluate(policy.NewMapNode("b", "Transcode", map[string]any{"quality": 720})); len(ds) != 0 {
+		t.Fatalf("check should not hold: %+v", ds)
+	}
+
+	if _, err := policycel.NewPolicy("bad", 0, "", "node.", policy.Decision{}); !errors.Is(err, policycel.ErrInvalidExpr) {
+		t.Fatalf("bad check: %v", err)
+	}
+}
+
+func TestNewPolicyClonesDecisionPerCall(t *testing.T) {
+	p, err := policycel.NewPolicy("cap", 0, "", "", policy.Decision{
+		Action:   policy.ActionAdjust,
+		Patch:    []policy.ParamOp{{Op: policy.OpSet, Key: "quality", Value: 1080}},
+		Metadata: map[string]any{"owner": "media"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := policy.NewMapNode("a", "Transcode", nil)
+	first := p.Check(n)[0]
+	first.Patch[0].Value = 0
+	first.Metadata["owner"] = "mutated"
+
+	second := p.Check(n)[0]
+	if second.Patch[0].Value != 1080 || second.Metadata["owner"] != "media" {
+		t.Fatalf("mutating one decision leaked into the next: %+v", second)
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+import "context"
+
+// overridePolicy prefers one policy's decisions and falls back to another's.
+type overridePolicy struct {
+	Policy // the override; provides ID, Priority, and Match
+	base   Policy
+}
+
+// Override returns a Policy implementing the "default with override" pattern.
+// Its ID, Priority, and Match are those of override; Check returns
+// override.Check(n) when it yields at least one Decision and base.Check(n)
+// otherwise. base is consulted only through Check, so it may be more general
+// than override: a specific policy can take over a generic one's decisions
+// without deregistering it. Register the result in place of both.
+//
+// The override's optional interfaces (PrecisePriority, Explainable,
+// Describable, ...) stay in effect. Under context-aware evaluation both
+// policies receive the context when they are ContextPolicy implementations.
+func Override(override, base Policy) Policy {
+	return overridePolicy{Policy: override, base: base}
+}
+
+func (p overridePolicy) Check(n Node) []Decision {
+	if ds := p.Policy.Check(n); len(ds) > 0 {
+		return ds
+	}
+	return p.base.Check(n)
+}
+
+// CheckContext is Check for context-aware evaluation.
+func (p overridePolicy) CheckContext(ctx context.Context, n Node) []Decision {
+	if ds := checkWithContext(ctx, p.Policy, n); len(ds) > 0 {
+		return ds
+	}
+	return checkWithContext(ctx, p.base, n)
+}
+
+func (p overridePolicy) unwrapPolicy() Policy                   { return p.Policy }
+func (p overridePolicy) wrapDecisions(ds []Decision) []Decision { return ds }
+func (p overridePolicy) wrapNode(n Node) Node                   { return n }
+func (p overridePolicy) rewrapPolicy(inner Policy) Policy {
+	p.Policy = inner
+	return p
+}
@@ -0,0 +1,44 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"reflect"
+	"testing"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+func TestOverride(t *testing.T) {
+	base := warnPolicy{id: "generic"}
+	specific, err := policy.PolicyFunc("specific", 1, func(policy.Node) bool { return true }, func(n policy.Node) []policy.Decision {
+		if n.Name() != "special" {
+			return nil
+		}
+		return []policy.Decision{{PolicyID: "specific", Action: policy.ActionCancelNode}}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := policy.New()
+	r.Register(policy.Override(specific, base))
+
+	if got := decisionIDs(r.Evaluate(&testNode{id: "1", name: "special"})); !reflect.DeepEqual(got, []string{"specific"}) {
+		t.Fatalf("override not applied: %v", got)
+	}
+	if got := decisionIDs(r.Evaluate(&testNode{id: "2", name: "plain"})); !reflect.DeepEqual(got, []string{"generic"}) {
+		t.Fatalf("fallback not applied: %v", got)
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+import "sync"
+
+// DecisionConsumer receives whole Decisions, as an alternative to Enforcer for
+// push-based pipelines (message queues, webhook dispatchers, ...).
+type DecisionConsumer interface {
+	// Consume handles one Decision. A non-nil error is reported to the caller
+	// of ConsumeDecisions and does not stop the remaining decisions.
+	Consume(d Decision) error
+}
+
+// ConsumeDecisions passes each Decision in ds to c, in order, and returns the
+// errors c reported (nil if none). A Decision with Stop == true is consumed and
+// then ends the run, mirroring Enforce.
+func ConsumeDecisions(c DecisionConsumer, ds []Decision) []error {
+	var errs []error
+	for _, d := range ds {
+		if err := c.Consume(d); err != nil {
+			errs = append(errs, err)
+		}
+		if d.Stop {
+			break
+		}
+	}
+	return errs
+}
+
+// MultiplexDecisions sends each Decision in ds to every consumer, running the
+// Consume calls for one Decision concurrently (one goroutine per consumer)
+// and waiting for all of them before moving to the next Decision, so each
+// consumer still sees ds in order. A Decision with Stop == true is delivered
+// to all consumers and then ends the run. The errors returned are ordered by
+// Decision, then by consumer (nil if none). A panicking Consume call cannot
+// be recovered by the caller, as it runs on its own goroutine, so it is
+// reported as a *PanicError in its place. Consumers must be safe for
+// concurrent use with each other, not with themselves.
+func MultiplexDecisions(ds []Decision, consumers []DecisionConsumer) []error {
+	var errs []error
+	round := make([]error, len(consumers))
+	for _, d := range ds {
+		var wg sync.WaitGroup
+		wg.Add(len(consumers))
+		for i, c := range consumers {
+			go func() {
+				defer wg.Done()
+				defer func() {
+					if v := recover(); v != nil {
+						round[i] = &PanicError{PolicyID: d.PolicyID, Value: v}
+					}
+				}()
+				round[i] = c.Consume(d)
+			}()
+		}
+		wg.Wait()
+		for i, err := range round {
+			if err != nil {
+				errs = append(errs, err)
+			}
+			round[i] = nil
+		}
+		if d.Stop {
+			break
+		}
+	}
+	return errs
+}
+
+// consumerEnforcer adapts a DecisionConsumer to Enforcer.
+type consumerEnforcer struct {
+	c DecisionConsumer
+}
+
+// AdapterEnforcer wraps c as an Enforcer for code paths that expect one.
+//
+// When used with Enforce (or another helper honouring EnforcerRouter), c
+// receives each original Decision unchanged. Direct calls to the Enforcer
+// methods are converted into an equivalent Decision. Enforcer methods cannot
+// report errors, so errors returned by c are discarded; use ConsumeDecisions
+// when they matter.
+func AdapterEnforcer(c DecisionConsumer) Enforcer {
+	return consumerEnforcer{c: c}
+}
+
+// Route implements EnforcerRouter, binding the full Decision for c.
+func (a consumerEnforcer) Route(d Decision) Enforcer {
+	return boundConsumer{c: a.c, d: d}
+}
+
+func (a consumerEnforcer) Adjust(scope Scope, fn func(map[string]any)) {
+	_ = a.c.Consume(Decision{Scope: scope, Action: ActionAdjust, Adjust: fn})
+}
+
+func (a consumerEnforcer) Cancel(scope Scope, reason error) {
+	action := ActionCancelNode
+	switch scope {
+	case ScopeSubtree:
+		action = ActionCancelSubtree
+	case ScopeRoot:
+		action = ActionCancelRoot
+	}
+	_ = a.c.Consume(Decision{Scope: scope, Action: action, Reason: reason})
+}
+
+func (a consumerEnforcer) Warn(policyID string, reason error) {
+	_ = a.c.Consume(Decision{PolicyID: policyID, Action: ActionWarn, Reason: reason})
+}
+
+func (a consumerEnforcer) Audit(policyID string, scope Scope, reason error) {
+	_ = a.c.Consume(Decision{PolicyID: policyID, Scope: scope, Action: ActionAudit, Reason: reason})
+}
+
+// boundConsumer forwards a single routed Decision to its consumer, whichever
+// Enforcer method Enforce picks for it.
+type boundConsumer struct {
+	c DecisionConsumer
+	d Decision
+}
+
+func (b boundConsumer) Adjust(Scope, func(map[string]any)) { _ = b.c.Consume(b.d) }
+func (b boundConsumer) Cancel(Scope, error)                { _ = b.c.Consume(b.d) }
+func (b boundConsumer) Warn(string, error)                 { _ = b.c.Consume(b.d) }
+func (b boundConsumer) Audit(string, Scope, error)         { _ = b.c.Consume(b.d) }
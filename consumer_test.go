@@ -0,0 +1,111 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+// sliceConsumer collects decisions and fails on a configured policy ID.
+type sliceConsumer struct {
+	got    []policy.Decision
+	failOn string
+}
+
+func (c *sliceConsumer) Consume(d policy.Decision) error {
+	c.got = append(c.got, d)
+	if d.PolicyID == c.failOn {
+		return errors.New("rejected " + d.PolicyID)
+	}
+	return nil
+}
+
+func TestConsumeDecisions(t *testing.T) {
+	c := &sliceConsumer{failOn: "bad"}
+	errs := policy.ConsumeDecisions(c, []policy.Decision{
+		{PolicyID: "ok"},
+		{PolicyID: "bad"},
+		{PolicyID: "stop", Stop: true},
+		{PolicyID: "never"},
+	})
+	if len(c.got) != 3 || len(errs) != 1 || errs[0].Error() != "rejected bad" {
+		t.Fatalf("consumed=%d errs=%v", len(c.got), errs)
+	}
+}
+
+func TestAdapterEnforcer(t *testing.T) {
+	c := &sliceConsumer{}
+	e := policy.AdapterEnforcer(c)
+
+	policy.Enforce(e, []policy.Decision{
+		{PolicyID: "W", Action: policy.ActionWarn, EnforcerTag: "kept"},
+		{PolicyID: "C", Action: policy.ActionCancelRoot, Scope: policy.ScopeRoot},
+	})
+	if len(c.got) != 2 || c.got[0].EnforcerTag != "kept" || c.got[1].PolicyID != "C" {
+		t.Fatalf("routed decisions %+v", c.got)
+	}
+
+	e.Cancel(policy.ScopeSubtree, nil)
+	if d := c.got[2]; d.Action != policy.ActionCancelSubtree || d.Scope != policy.ScopeSubtree {
+		t.Fatalf("direct Cancel produced %+v", d)
+	}
+}
+
+func TestMultiplexDecisions(t *testing.T) {
+	a := &sliceConsumer{failOn: "bad"}
+	b := &sliceConsumer{}
+	c := &sliceConsumer{failOn: "bad"}
+	errs := policy.MultiplexDecisions([]policy.Decision{
+		{PolicyID: "ok"},
+		{PolicyID: "bad"},
+		{PolicyID: "stop", Stop: true},
+		{PolicyID: "never"},
+	}, []policy.DecisionConsumer{a, b, c})
+
+	if len(errs) != 2 {
+		t.Fatalf("expected one error from each failing consumer, got %v", errs)
+	}
+	for _, sc := range []*sliceConsumer{a, b, c} {
+		if got := decisionIDs(sc.got); !reflect.DeepEqual(got, []string{"ok", "bad", "stop"}) {
+			t.Fatalf("consumer saw %v", got)
+		}
+	}
+}
+
+// panicConsumer panics on every Decision.
+type panicConsumer struct{}
+
+func (panicConsumer) Consume(d policy.Decision) error { panic("boom") }
+
+func TestMultiplexDecisionsRecoversPanics(t *testing.T) {
+	ok := &sliceConsumer{}
+	errs := policy.MultiplexDecisions([]policy.Decision{{PolicyID: "a"}, {PolicyID: "b"}},
+		[]policy.DecisionConsumer{panicConsumer{}, ok})
+
+	if len(errs) != 2 {
+		t.Fatalf("expected one error per decision, got %v", errs)
+	}
+	var pe *policy.PanicError
+	if !errors.As(errs[1], &pe) || pe.PolicyID != "b" || pe.Value != "boom" {
+		t.Fatalf("errs[1] = %v, want a PanicError for b", errs[1])
+	}
+	if got := decisionIDs(ok.got); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Fatalf("healthy consumer saw %v", got)
+	}
+}
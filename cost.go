@@ -0,0 +1,58 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+// EnforcementCost summarises the Enforcer calls a decision set would cause,
+// as computed by EstimateEnforceCost.
+type EnforcementCost struct {
+	AdjustCount int  // Adjust calls (ActionAdjust with an Adjust or Patch)
+	CancelCount int  // Cancel calls (any Cancel* action)
+	WarnCount   int  // Warn calls
+	AuditCount  int  // ActionAudit decisions (Audit calls on an AuditEnforcer)
+	StopPresent bool // a Decision with Stop == true was reached
+}
+
+// Total returns the number of Enforcer calls counted in c.
+func (c EnforcementCost) Total() int {
+	return c.AdjustCount + c.CancelCount + c.WarnCount + c.AuditCount
+}
+
+// EstimateEnforceCost counts the calls Enforce(e, ds) would make, without
+// running an Enforcer, so hosts can refuse decision sets above a cost
+// threshold. Decisions after the first Stop are not counted, and Noop
+// decisions and Adjust decisions with neither a function nor a Patch cost
+// nothing, exactly as Enforce skips them.
+func EstimateEnforceCost(ds []Decision) EnforcementCost {
+	var c EnforcementCost
+	for _, d := range ds {
+		switch d.Action {
+		case ActionWarn:
+			c.WarnCount++
+		case ActionAdjust:
+			if EffectiveAdjust(d) != nil {
+				c.AdjustCount++
+			}
+		case ActionCancelNode, ActionCancelSubtree, ActionCancelRoot:
+			c.CancelCount++
+		case ActionAudit:
+			c.AuditCount++
+		}
+		if d.Stop {
+			c.StopPresent = true
+			break
+		}
+	}
+	return c
+}
@@ -0,0 +1,52 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"testing"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+func TestEstimateEnforceCostMatchesEnforce(t *testing.T) {
+	adjust := func(map[string]any) {}
+	ds := []policy.Decision{
+		{Action: policy.ActionNoop},
+		{Action: policy.ActionWarn},
+		{Action: policy.ActionAdjust, Adjust: adjust},
+		{Action: policy.ActionAdjust},
+		{Action: policy.ActionAudit},
+		{Action: policy.ActionCancelSubtree, Stop: true},
+		{Action: policy.ActionWarn},
+	}
+	got := policy.EstimateEnforceCost(ds)
+	want := policy.EnforcementCost{AdjustCount: 1, CancelCount: 1, WarnCount: 1, AuditCount: 1, StopPresent: true}
+	if got != want {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	if got.Total() != 4 {
+		t.Fatalf("Total() = %d, want 4", got.Total())
+	}
+
+	e := &recEnforcer{}
+	policy.Enforce(e, ds)
+	if len(e.adjusts) != got.AdjustCount || len(e.cancels) != got.CancelCount || len(e.warns) != got.WarnCount {
+		t.Fatalf("estimate %+v disagrees with Enforce %+v", got, e)
+	}
+
+	if c := policy.EstimateEnforceCost(nil); c != (policy.EnforcementCost{}) {
+		t.Fatalf("empty set cost %+v", c)
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// ErrPossibleDeadlock is returned by DeadlockDetectingEvaluate when
+// evaluation timed out while blocked acquiring a lock.
+var ErrPossibleDeadlock = errors.New("ccxpolicy: possible deadlock in policy evaluation")
+
+// DeadlockDetectingEvaluate runs Evaluate(n) on a separate goroutine and
+// waits for it until ctx is done or timeout (if > 0) elapses. When
+// evaluation finishes in time its Decisions are returned. Otherwise the
+// evaluating goroutine's stack is inspected: if it is parked on a lock
+// (sync.Mutex, sync.RWMutex, or another semaphore wait) the error wraps both
+// ErrPossibleDeadlock and the context error, and names the wait; if it is
+// busy or waiting on something else, the context error is returned alone.
+//
+// This is a diagnostic for policy authors using external synchronization.
+// Go cannot abort a goroutine, so on timeout the evaluation keeps running in
+// the background and its result is discarded.
+func (r *Registry) DeadlockDetectingEvaluate(ctx context.Context, n Node, timeout time.Duration) ([]Decision, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	gid := make(chan string, 1)
+	done := make(chan []Decision, 1)
+	go func() {
+		gid <- currentGoroutineID()
+		done <- r.Evaluate(n)
+	}()
+	id := <-gid
+
+	select {
+	case ds := <-done:
+		return ds, nil
+	case <-ctx.Done():
+		if wait, ok := lockWait(id); ok {
+			return nil, fmt.Errorf("%w: evaluating goroutine blocked in %s: %w", ErrPossibleDeadlock, wait, ctx.Err())
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// DeadlockDetectingEvaluate runs Registry.DeadlockDetectingEvaluate against
+// the global registry.
+func DeadlockDetectingEvaluate(ctx context.Context, n Node, timeout time.Duration) ([]Decision, error) {
+	return defaultRegistry.DeadlockDetectingEvaluate(ctx, n, timeout)
+}
+
+// currentGoroutineID returns the ID of the calling goroutine as printed in
+// stack dumps.
+func currentGoroutineID() string {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	fields := bytes.Fields(buf) // "goroutine", "<id>", "[running]:", ...
+	if len(fields) < 2 {
+		return ""
+	}
+	return string(fields[1])
+}
+
+// lockWait reports the wait reason of goroutine id when it is blocked on a
+// lock, as shown in a full stack dump ("goroutine 7 [sync.Mutex.Lock]:").
+func lockWait(id string) (string, bool) {
+	if id == "" {
+		return "", false
+	}
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+	prefix := "goroutine " + id + " ["
+	for _, line := range strings.Split(string(buf), "\n") {
+		if !strings.HasPrefix(line, prefix) {
+			continue
+		}
+		wait, _, _ := strings.Cut(line[len(prefix):], "]")
+		wait, _, _ = strings.Cut(wait, ",") // drop ", N minutes"
+		if strings.HasPrefix(wait, "sync.") || strings.HasPrefix(wait, "semacquire") {
+			return wait, true
+		}
+		return "", false
+	}
+	return "", false
+}
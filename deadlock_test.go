@@ -0,0 +1,72 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+// blockingPolicy runs wait in Check.
+func blockingPolicy(id string, wait func()) policy.Policy {
+	return &policy.FuncPolicy{
+		PolicyID: id,
+		MatchFn:  func(policy.Node) bool { return true },
+		CheckFn: func(policy.Node) []policy.Decision {
+			wait()
+			return nil
+		},
+	}
+}
+
+func TestDeadlockDetectingEvaluate(t *testing.T) {
+	n := &testNode{id: "n"}
+
+	r := policy.New()
+	r.Register(warnPolicy{id: "w"})
+	ds, err := r.DeadlockDetectingEvaluate(context.Background(), n, time.Second)
+	if err != nil || len(ds) != 1 {
+		t.Fatalf("fast evaluation: %v %v", ds, err)
+	}
+
+	var mu sync.Mutex
+	mu.Lock()
+	defer mu.Unlock()
+	r = policy.New()
+	r.Register(blockingPolicy("locker", func() { mu.Lock(); mu.Unlock() }))
+	_, err = r.DeadlockDetectingEvaluate(context.Background(), n, 50*time.Millisecond)
+	if !errors.Is(err, policy.ErrPossibleDeadlock) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("lock wait: got %v", err)
+	}
+
+	release := make(chan struct{})
+	defer close(release)
+	r = policy.New()
+	r.Register(blockingPolicy("sleeper", func() {
+		select {
+		case <-release:
+		case <-time.After(time.Second):
+		}
+	}))
+	_, err = r.DeadlockDetectingEvaluate(context.Background(), n, 50*time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) || errors.Is(err, policy.ErrPossibleDeadlock) {
+		t.Fatalf("non-lock wait: got %v", err)
+	}
+}
@@ -0,0 +1,282 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package declarative builds ccxpolicy policies from JSON or YAML
+// definitions, so operators can ship policy changes as configuration instead
+// of recompiling. It lives in its own module so that the core package stays
+// free of third-party dependencies:
+//
+//	import "github.com/ArieDeha/ccxpolicy/declarative"
+//
+// A document holds a list of policies:
+//
+//	policies:
+//	  - id: cap-quality
+//	    priority: 300
+//	    match:
+//	      names: ["Transcode*"]        # path.Match globs on Node.Name()
+//	      expr: param.quality > 1080   # see ccxpolicy.ParseMatchExpr
+//	    action: Adjust                 # any ccxpolicy.Action name
+//	    scope: Subtree                 # Node (default), Subtree, or Root
+//	    set: {quality: 1080}           # params written by Adjust
+//	    reason: quality above cap
+//	  - id: no-legacy
+//	    priority: 100
+//	    match: {names: ["Legacy*"]}
+//	    action: CancelSubtree
+//	    reason: legacy pipelines are disabled
+//	    stop: true
+//
+// A policy matches when its name globs (if any) and its expression (if any)
+// both match; an empty match section matches every node. Each matching node
+// yields one Decision built from the remaining fields.
+package declarative
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+// ErrInvalidSpec is wrapped by the errors returned for malformed definitions.
+var ErrInvalidSpec = errors.New("declarative: invalid policy spec")
+
+// ErrIDConflict is wrapped by the error Apply returns when a policy in the
+// config has the ID of a policy registered by other code.
+var ErrIDConflict = errors.New("declarative: policy ID registered outside the config")
+
+// Spec is a policy document.
+type Spec struct {
+	Policies []PolicySpec `json:"policies" yaml:"policies"`
+}
+
+// PolicySpec defines one policy.
+type PolicySpec struct {
+	ID       string    `json:"id" yaml:"id"`
+	Priority int       `json:"priority" yaml:"priority"`
+	Match    MatchSpec `json:"match" yaml:"match"`
+	// Action is an Action name as printed by Action.String (e.g. "Warn").
+	Action string `json:"action" yaml:"action"`
+	// Scope is a Scope name; empty means "Node".
+	Scope  string `json:"scope,omitempty" yaml:"scope,omitempty"`
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+	Stop   bool   `json:"stop,omitempty" yaml:"stop,omitempty"`
+	// Set lists the params an Adjust decision writes. It is required for
+	// Adjust and not allowed for other actions.
+	Set         map[string]any `json:"set,omitempty" yaml:"set,omitempty"`
+	EnforcerTag string         `json:"enforcer_tag,omitempty" yaml:"enforcer_tag,omitempty"`
+}
+
+// MatchSpec selects the nodes a policy applies to.
+type MatchSpec struct {
+	// Names are path.Match globs; a node matches if its Name() matches any.
+	Names []string `json:"names,omitempty" yaml:"names,omitempty"`
+	// Expr is a ccxpolicy.ParseMatchExpr expression.
+	Expr string `json:"expr,omitempty" yaml:"expr,omitempty"`
+}
+
+// ParseJSON decodes a JSON document and compiles it. Unknown fields are
+// rejected.
+func ParseJSON(data []byte) ([]policy.Policy, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var s Spec
+	if err := dec.Decode(&s); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSpec, err)
+	}
+	return Compile(s)
+}
+
+// ParseYAML decodes a YAML document and compiles it. Unknown fields are
+// rejected.
+func ParseYAML(data []byte) ([]policy.Policy, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	var s Spec
+	if err := dec.Decode(&s); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSpec, err)
+	}
+	return Compile(s)
+}
+
+// Compile turns s into policies, in document order. It validates every
+// definition (non-empty unique IDs, known action and scope names, valid
+// globs and expressions, Set present exactly for Adjust) and returns an
+// error wrapping ErrInvalidSpec for the first problem found.
+func Compile(s Spec) ([]policy.Policy, error) {
+	out := make([]policy.Policy, 0, len(s.Policies))
+	seen := map[string]bool{}
+	for i, ps := range s.Policies {
+		p, err := compile(ps)
+		if err != nil {
+			return nil, fmt.Errorf("%w: policies[%d] %q: %v", ErrInvalidSpec, i, ps.ID, err)
+		}
+		if seen[ps.ID] {
+			return nil, fmt.Errorf("%w: policies[%d]: duplicate id %q", ErrInvalidSpec, i, ps.ID)
+		}
+		seen[ps.ID] = true
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// Apply makes ps the declarative policy set of r in a single
+// Registry.Transaction: a policy whose ID is already registered is replaced,
+// any other is added, and every policy installed by an earlier Apply (that
+// is, built by Compile, even if since wrapped by e.g. SwapPriorities) whose
+// ID is not in ps is removed, so deleting a policy from the config and
+// reloading it deregisters the policy. Policies registered by other means
+// are left alone: if one has the ID of a policy in ps, Apply returns an error
+// wrapping ErrIDConflict. Either all of the changes take effect or, on error,
+// none. A nil r is not allowed.
+func Apply(r *policy.Registry, ps []policy.Policy) error {
+	keep := make(map[string]bool, len(ps))
+	for _, p := range ps {
+		keep[p.ID()] = true
+	}
+	return r.Transaction(func(tx *policy.RegistryTransaction) error {
+		for _, p := range tx.Policies() {
+			owned := isCompiled(p)
+			switch {
+			case !owned && keep[p.ID()]:
+				return fmt.Errorf("%w: %q", ErrIDConflict, p.ID())
+			case owned && !keep[p.ID()]:
+				if err := tx.Deregister(p.ID()); err != nil {
+					return err
+				}
+			}
+		}
+		for _, p := range ps {
+			err := tx.Replace(p)
+			if errors.Is(err, policy.ErrPolicyNotFound) {
+				err = tx.Register(p)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// isCompiled reports whether p, or a policy it wraps, was built by Compile.
+func isCompiled(p policy.Policy) bool {
+	for ; p != nil; p = policy.UnwrapPolicy(p) {
+		if _, ok := p.(*compiledPolicy); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// compiledPolicy is a Policy built from a PolicySpec.
+type compiledPolicy struct {
+	id       string
+	priority int
+	match    policy.MatchFunc
+	decision policy.Decision
+}
+
+func compile(ps PolicySpec) (*compiledPolicy, error) {
+	if ps.ID == "" {
+		return nil, errors.New("missing id")
+	}
+	action, err := policy.ParseAction(ps.Action)
+	if err != nil {
+		return nil, err
+	}
+	scope := policy.ScopeNode
+	if ps.Scope != "" {
+		if scope, err = policy.ParseScope(ps.Scope); err != nil {
+			return nil, err
+		}
+	}
+	switch {
+	case action == policy.ActionAdjust && len(ps.Set) == 0:
+		return nil, errors.New("Adjust requires set")
+	case action != policy.ActionAdjust && len(ps.Set) > 0:
+		return nil, fmt.Errorf("set is only allowed with Adjust, not %s", action)
+	}
+	match, err := compileMatch(ps.Match)
+	if err != nil {
+		return nil, err
+	}
+
+	d := policy.Decision{
+		PolicyID:    ps.ID,
+		Scope:       scope,
+		Action:      action,
+		Stop:        ps.Stop,
+		EnforcerTag: ps.EnforcerTag,
+	}
+	if ps.Reason != "" {
+		d.Reason = policy.Reason(ps.Reason)
+	}
+	for _, k := range slices.Sorted(maps.Keys(ps.Set)) {
+		d.Patch = append(d.Patch, policy.ParamOp{Op: policy.OpSet, Key: k, Value: ps.Set[k]})
+	}
+	return &compiledPolicy{id: ps.ID, priority: ps.Priority, match: match, decision: d}, nil
+}
+
+// compileMatch combines the name globs and the expression of m.
+func compileMatch(m MatchSpec) (policy.MatchFunc, error) {
+	for _, g := range m.Names {
+		if _, err := path.Match(g, ""); err != nil {
+			return nil, fmt.Errorf("name glob %q: %v", g, err)
+		}
+	}
+	var expr policy.MatchFunc
+	if m.Expr != "" {
+		var err error
+		if expr, err = policy.ParseMatchExpr(m.Expr); err != nil {
+			return nil, err
+		}
+	}
+	names := m.Names
+	return func(n policy.Node) bool {
+		if len(names) > 0 && !matchAnyGlob(names, n.Name()) {
+			return false
+		}
+		return expr == nil || expr(n)
+	}, nil
+}
+
+func matchAnyGlob(globs []string, name string) bool {
+	for _, g := range globs {
+		if ok, _ := path.Match(g, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *compiledPolicy) ID() string               { return p.id }
+func (p *compiledPolicy) Priority() int            { return p.priority }
+func (p *compiledPolicy) Match(n policy.Node) bool { return p.match(n) }
+
+// Check returns the policy's Decision. Set is expressed as a Patch of OpSet
+// ops (in key order), so the Decision stays serialisable.
+func (p *compiledPolicy) Check(policy.Node) []policy.Decision {
+	d := p.decision
+	d.Patch = slices.Clone(d.Patch)
+	return []policy.Decision{d}
+}
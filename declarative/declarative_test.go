@@ -0,0 +1,160 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package declarative_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+	"github.com/ArieDeha/ccxpolicy/declarative"
+)
+
+const doc = `
+policies:
+  - id: cap-quality
+    priority: 300
+    match:
+      names: ["Transcode*"]
+      expr: param.quality > 1080
+    action: Adjust
+    scope: Subtree
+    set: {quality: 1080}
+    reason: quality above cap
+  - id: no-legacy
+    priority: 100
+    match: {names: ["Legacy*"]}
+    action: CancelSubtree
+    reason: legacy pipelines are disabled
+    stop: true
+`
+
+func TestParseYAMLAndApply(t *testing.T) {
+	ps, err := declarative.ParseYAML([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := policy.New()
+	if err := declarative.Apply(r, ps); err != nil {
+		t.Fatal(err)
+	}
+
+	n := policy.NewMapNode("n1", "TranscodeHD", map[string]any{"quality": 2160})
+	ds := r.Evaluate(n)
+	if len(ds) != 1 || ds[0].PolicyID != "cap-quality" || ds[0].Scope != policy.ScopeSubtree || ds[0].Reason.Error() != "quality above cap" {
+		t.Fatalf("decisions = %+v", ds)
+	}
+	params := map[string]any{"quality": 2160}
+	policy.EffectiveAdjust(ds[0])(params)
+	if !reflect.DeepEqual(params, map[string]any{"quality": 1080}) {
+		t.Fatalf("adjusted params = %v", params)
+	}
+
+	if ds := r.Evaluate(policy.NewMapNode("n2", "TranscodeSD", map[string]any{"quality": 480})); len(ds) != 0 {
+		t.Fatalf("expression should not match: %+v", ds)
+	}
+	if ds := r.Evaluate(policy.NewMapNode("n3", "LegacyIngest", nil)); len(ds) != 1 || ds[0].Action != policy.ActionCancelSubtree || !ds[0].Stop {
+		t.Fatalf("legacy decisions = %+v", ds)
+	}
+}
+
+func TestParseJSONAndReapply(t *testing.T) {
+	r := policy.New()
+	for _, reason := range []string{"v1", "v2"} {
+		ps, err := declarative.ParseJSON([]byte(`{"policies":[{"id":"w","action":"Warn","reason":"` + reason + `"}]}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := declarative.Apply(r, ps); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ds := r.Evaluate(policy.NewMapNode("n", "Any", nil))
+	if len(ds) != 1 || ds[0].Reason.Error() != "v2" {
+		t.Fatalf("re-applying should replace the policy, got %+v", ds)
+	}
+}
+
+func TestReapplyRemovesDeletedPolicies(t *testing.T) {
+	r := policy.New()
+	r.Register(&policy.FuncPolicy{
+		PolicyID: "manual",
+		MatchFn:  func(policy.Node) bool { return true },
+		CheckFn:  func(policy.Node) []policy.Decision { return nil },
+	})
+	for i, doc := range []string{
+		`{"policies":[{"id":"a","action":"Warn"},{"id":"b","action":"Warn"}]}`,
+		`{"policies":[{"id":"b","action":"Warn"}]}`,
+	} {
+		ps, err := declarative.ParseJSON([]byte(doc))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := declarative.Apply(r, ps); err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 {
+			// A wrapped config policy must still be recognised as owned.
+			if err := r.SwapPriorities("a", "manual"); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	var ids []string
+	for _, p := range r.Policies() {
+		ids = append(ids, p.ID())
+	}
+	if want := []string{"b", "manual"}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("policies after reload = %v, want %v", ids, want)
+	}
+}
+
+func TestApplyRejectsIDOfForeignPolicy(t *testing.T) {
+	r := policy.New()
+	r.Register(&policy.FuncPolicy{
+		PolicyID: "a",
+		MatchFn:  func(policy.Node) bool { return true },
+		CheckFn:  func(policy.Node) []policy.Decision { return nil },
+	})
+	ps, err := declarative.ParseJSON([]byte(`{"policies":[{"id":"a","action":"Warn"},{"id":"b","action":"Warn"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := declarative.Apply(r, ps); !errors.Is(err, declarative.ErrIDConflict) {
+		t.Fatalf("Apply = %v, want ErrIDConflict", err)
+	}
+	if got := len(r.Policies()); got != 1 {
+		t.Fatalf("a failed Apply changed the registry: %d policies", got)
+	}
+}
+
+func TestInvalidSpecs(t *testing.T) {
+	for name, src := range map[string]string{
+		"unknown field": `{"policies":[{"id":"a","action":"Warn","colour":"red"}]}`,
+		"missing id":    `{"policies":[{"action":"Warn"}]}`,
+		"bad action":    `{"policies":[{"id":"a","action":"Explode"}]}`,
+		"bad scope":     `{"policies":[{"id":"a","action":"Warn","scope":"Galaxy"}]}`,
+		"adjust no set": `{"policies":[{"id":"a","action":"Adjust"}]}`,
+		"set on warn":   `{"policies":[{"id":"a","action":"Warn","set":{"x":1}}]}`,
+		"bad glob":      `{"policies":[{"id":"a","action":"Warn","match":{"names":["["]}}]}`,
+		"bad expr":      `{"policies":[{"id":"a","action":"Warn","match":{"expr":"name =="}}]}`,
+		"duplicate id":  `{"policies":[{"id":"a","action":"Warn"},{"id":"a","action":"Warn"}]}`,
+	} {
+		if _, err := declarative.ParseJSON([]byte(src)); !errors.Is(err, declarative.ErrInvalidSpec) {
+			t.Errorf("%s: got %v, want ErrInvalidSpec", name, err)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+import "time"
+
+// PolicyResult is the outcome of one policy within an EvalResult.
+type PolicyResult struct {
+	PolicyID string
+	// Matched reports whether Match(n) returned true. It is false for
+	// skipped policies.
+	Matched bool
+	// Skipped is true when the policy was not consulted because an earlier
+	// policy returned a Stop decision.
+	Skipped bool
+	// Duration is the time spent in Match and Check; zero when Skipped.
+	Duration time.Duration
+	// Decisions are the policy's contributions to EvalResult.Decisions.
+	Decisions []Decision
+	// Err is the *PanicError recovered from Match or Check, if any. The
+	// policy's Decisions are then the warning Evaluate emits for it.
+	Err error
+}
+
+// EvalResult is the detailed outcome of evaluating a node; see
+// Registry.EvaluateDetailed.
+type EvalResult struct {
+	// Decisions is what Evaluate would return, without middleware.
+	Decisions []Decision
+	// Policies holds one entry per registered policy, in evaluation order.
+	Policies []PolicyResult
+	// StoppedBy is the ID of the policy whose Stop decision ended evaluation,
+	// or "" if every policy ran.
+	StoppedBy string
+	// Duration is the wall time of the whole evaluation.
+	Duration time.Duration
+}
+
+// Matched returns the IDs of the policies whose Match returned true.
+func (res EvalResult) Matched() []string {
+	return res.policyIDs(func(pr PolicyResult) bool { return pr.Matched })
+}
+
+// Skipped returns the IDs of the policies not consulted because of a Stop.
+func (res EvalResult) Skipped() []string {
+	return res.policyIDs(func(pr PolicyResult) bool { return pr.Skipped })
+}
+
+func (res EvalResult) policyIDs(keep func(PolicyResult) bool) []string {
+	var ids []string
+	for _, pr := range res.Policies {
+		if keep(pr) {
+			ids = append(ids, pr.PolicyID)
+		}
+	}
+	return ids
+}
+
+// EvaluateDetailed evaluates n like Evaluate and reports, per policy, whether
+// it matched or was skipped, how long it took and what it returned, to answer
+// "why did (or didn't) this policy fire?". Middleware registered with Use is
+// not applied, since it may not run the policies at all. Panics are recovered
+// as in Evaluate and also recorded in PolicyResult.Err.
+func (r *Registry) EvaluateDetailed(n Node) EvalResult {
+	return r.evaluateDetailed(n, r.snapshot())
+}
+
+// evaluateDetailed implements EvaluateDetailed over pols, a snapshot of the
+// registered policies.
+func (r *Registry) evaluateDetailed(n Node, pols []Policy) EvalResult {
+	start := time.Now()
+	hooks := r.currentHooks()
+	res := EvalResult{Policies: make([]PolicyResult, len(pols))}
+	for i, p := range pols {
+		pr := &res.Policies[i]
+		pr.PolicyID = p.ID()
+		if res.StoppedBy != "" {
+			pr.Skipped = true
+			continue
+		}
+		t0 := time.Now()
+		matched, ds, err := r.inspectCheck(p, n)
+		pr.Duration = time.Since(t0)
+		pr.Matched, pr.Err = matched, err
+		if err != nil {
+			ds = r.panicWarning(p, err)
+		}
+		for j, d := range ds {
+			if d.Stop {
+				ds = ds[:j+1]
+				res.StoppedBy = pr.PolicyID
+				if hooks.OnStop != nil {
+					hooks.OnStop(d, pols[i+1:])
+				}
+				break
+			}
+		}
+		pr.Decisions = ds
+		res.Decisions = append(res.Decisions, ds...)
+	}
+	res.Duration = time.Since(start)
+	return res
+}
+
+// EvaluateDetailed runs Registry.EvaluateDetailed against the global registry.
+func EvaluateDetailed(n Node) EvalResult { return defaultRegistry.EvaluateDetailed(n) }
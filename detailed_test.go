@@ -0,0 +1,78 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+func TestEvaluateDetailed(t *testing.T) {
+	r := policy.New()
+	r.Register(warnPolicy{id: "a", prio: 1})
+	r.Register(&policy.FuncPolicy{
+		PolicyID: "miss",
+		Prio:     2,
+		MatchFn:  func(policy.Node) bool { return false },
+		CheckFn:  func(policy.Node) []policy.Decision { return nil },
+	})
+	r.Register(&policy.FuncPolicy{
+		PolicyID: "boom",
+		Prio:     3,
+		MatchFn:  func(policy.Node) bool { return true },
+		CheckFn:  func(policy.Node) []policy.Decision { panic("check") },
+	})
+	r.Register(&policy.FuncPolicy{
+		PolicyID: "stop",
+		Prio:     4,
+		MatchFn:  func(policy.Node) bool { return true },
+		CheckFn: func(policy.Node) []policy.Decision {
+			return []policy.Decision{
+				{Action: policy.ActionCancelNode, Stop: true},
+				{Action: policy.ActionWarn},
+			}
+		},
+	})
+	r.Register(warnPolicy{id: "late", prio: 5})
+
+	n := &testNode{id: "n"}
+	res := r.EvaluateDetailed(n)
+
+	if got, want := decisionIDs(res.Decisions), decisionIDs(r.Evaluate(n)); !reflect.DeepEqual(got, want) {
+		t.Fatalf("decisions %v, Evaluate gives %v", got, want)
+	}
+	if got := res.Matched(); !reflect.DeepEqual(got, []string{"a", "boom", "stop"}) {
+		t.Fatalf("matched %v", got)
+	}
+	if got := res.Skipped(); !reflect.DeepEqual(got, []string{"late"}) {
+		t.Fatalf("skipped %v", got)
+	}
+	if res.StoppedBy != "stop" || len(res.Policies) != 5 {
+		t.Fatalf("result %+v", res)
+	}
+	var pe *policy.PanicError
+	if boom := res.Policies[2]; !errors.As(boom.Err, &pe) || len(boom.Decisions) != 1 || boom.Decisions[0].Action != policy.ActionWarn {
+		t.Fatalf("panicking policy %+v", boom)
+	}
+	if stop := res.Policies[3]; len(stop.Decisions) != 1 {
+		t.Fatalf("decisions after Stop kept: %+v", stop.Decisions)
+	}
+	if late := res.Policies[4]; late.Duration != 0 || late.Decisions != nil {
+		t.Fatalf("skipped policy %+v", late)
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+import (
+	"maps"
+	"reflect"
+)
+
+// ParamChange records the old and new value of a changed parameter.
+type ParamChange struct {
+	Before, After any
+}
+
+// NodeChanges describes how a node's Params differ between two observations.
+// Each map is nil when it has no entries.
+type NodeChanges struct {
+	ParamAdded   map[string]any         // keys only present after, with their value
+	ParamRemoved map[string]any         // keys only present before, with their value
+	ParamChanged map[string]ParamChange // keys whose value differs (reflect.DeepEqual)
+}
+
+// Empty reports whether no parameter changed.
+func (c NodeChanges) Empty() bool {
+	return len(c.ParamAdded) == 0 && len(c.ParamRemoved) == 0 && len(c.ParamChanged) == 0
+}
+
+// NodeDiff compares before.Params() with after.Params(). The nodes are
+// usually two observations of the same node; only Params are compared. A nil
+// node counts as having no params.
+func NodeDiff(before, after Node) NodeChanges {
+	b, a := nodeParams(before), nodeParams(after)
+	var c NodeChanges
+	for k, av := range a {
+		bv, ok := b[k]
+		switch {
+		case !ok:
+			if c.ParamAdded == nil {
+				c.ParamAdded = map[string]any{}
+			}
+			c.ParamAdded[k] = av
+		case !reflect.DeepEqual(bv, av):
+			if c.ParamChanged == nil {
+				c.ParamChanged = map[string]ParamChange{}
+			}
+			c.ParamChanged[k] = ParamChange{Before: bv, After: av}
+		}
+	}
+	for k, bv := range b {
+		if _, ok := a[k]; !ok {
+			if c.ParamRemoved == nil {
+				c.ParamRemoved = map[string]any{}
+			}
+			c.ParamRemoved[k] = bv
+		}
+	}
+	return c
+}
+
+func nodeParams(n Node) map[string]any {
+	if n == nil {
+		return nil
+	}
+	return n.Params()
+}
+
+// paramSnapshot freezes a node's Params at the time it was taken.
+type paramSnapshot struct {
+	Node
+	params map[string]any
+}
+
+func (s paramSnapshot) Params() map[string]any { return s.params }
+
+// SnapshotNode returns a view of n whose Params() are frozen at the time of
+// the call (a shallow copy; nested values are shared). ID, Name, and lineage
+// still delegate to n.
+func SnapshotNode(n Node) Node {
+	return paramSnapshot{Node: n, params: maps.Clone(n.Params())}
+}
+
+// ObservingEvaluate evaluates n like Evaluate and reports how n's Params
+// changed while it ran, comparing a snapshot taken before with n.Params()
+// read afterwards (so a host whose Params() returns a copy must refresh it
+// before returning).
+//
+// Evaluate itself is read-only, so a non-empty NodeChanges points at a policy
+// mutating Params in Match or Check, or at a concurrent writer. To observe
+// the effect of enforcement instead, snapshot around Enforce and use
+// NodeDiff. The snapshot is shallow: in-place changes to nested values are
+// not detected.
+func (r *Registry) ObservingEvaluate(n Node) ([]Decision, NodeChanges) {
+	before := SnapshotNode(n)
+	ds := r.Evaluate(n)
+	return ds, NodeDiff(before, n)
+}
+
+// ObservingEvaluate runs Registry.ObservingEvaluate on the global registry.
+func ObservingEvaluate(n Node) ([]Decision, NodeChanges) {
+	return defaultRegistry.ObservingEvaluate(n)
+}
@@ -0,0 +1,61 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"reflect"
+	"testing"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+func TestNodeDiff(t *testing.T) {
+	before := &testNode{id: "n", params: map[string]any{"keep": 1, "drop": "x", "tune": 720}}
+	after := &testNode{id: "n", params: map[string]any{"keep": 1, "tune": 1080, "new": true}}
+
+	c := policy.NodeDiff(before, after)
+	want := policy.NodeChanges{
+		ParamAdded:   map[string]any{"new": true},
+		ParamRemoved: map[string]any{"drop": "x"},
+		ParamChanged: map[string]policy.ParamChange{"tune": {Before: 720, After: 1080}},
+	}
+	if !reflect.DeepEqual(c, want) {
+		t.Fatalf("NodeDiff = %+v, want %+v", c, want)
+	}
+	if !policy.NodeDiff(before, before).Empty() {
+		t.Fatalf("identical nodes reported changes")
+	}
+}
+
+// mutatingPolicy misbehaves by writing to Params during Check.
+type mutatingPolicy struct{ warnPolicy }
+
+func (p mutatingPolicy) Check(n policy.Node) []policy.Decision {
+	n.Params()["touched"] = true
+	return p.warnPolicy.Check(n)
+}
+
+func TestObservingEvaluate(t *testing.T) {
+	r := policy.New()
+	r.Register(mutatingPolicy{warnPolicy{id: "m"}})
+
+	ds, c := r.ObservingEvaluate(&testNode{id: "n", params: map[string]any{}})
+	if len(ds) != 1 {
+		t.Fatalf("decisions %+v", ds)
+	}
+	if !reflect.DeepEqual(c.ParamAdded, map[string]any{"touched": true}) {
+		t.Fatalf("changes = %+v", c)
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// PolicyDependencies is an optional interface for policies that rely on other
+// policies (for example on parameters those policies adjust). ExportDOT draws
+// the dependencies it reports.
+type PolicyDependencies interface {
+	// DependsOn returns the IDs of the policies this policy depends on.
+	DependsOn() []string
+}
+
+// DOTEvaluateNode is the name of the virtual node ExportDOT links every
+// policy to.
+const DOTEvaluateNode = "Evaluate"
+
+// ExportDOT writes r's policies to w as a Graphviz DOT digraph: one node per
+// policy, in evaluation order, each with an edge to the virtual
+// DOTEvaluateNode labelled with its EffectivePriority, plus a dashed edge from
+// a policy to every ID its PolicyDependencies reports. Render it with, e.g.,
+// "dot -Tsvg".
+func (r *Registry) ExportDOT(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("digraph ccxpolicy {\n\trankdir=LR;\n")
+	fmt.Fprintf(&b, "\t%s [shape=doublecircle];\n", dotID(DOTEvaluateNode))
+	pols := r.snapshot()
+	for _, p := range pols {
+		fmt.Fprintf(&b, "\t%s [shape=box];\n", dotID(p.ID()))
+	}
+	for _, p := range pols {
+		fmt.Fprintf(&b, "\t%s -> %s [label=%s];\n",
+			dotID(p.ID()), dotID(DOTEvaluateNode), dotID(fmt.Sprintf("%g", EffectivePriority(p))))
+	}
+	for _, p := range pols {
+		pd, ok := asPolicy[PolicyDependencies](p)
+		if !ok {
+			continue
+		}
+		for _, dep := range pd.DependsOn() {
+			fmt.Fprintf(&b, "\t%s -> %s [style=dashed];\n", dotID(p.ID()), dotID(dep))
+		}
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ExportDOT runs Registry.ExportDOT against the global registry.
+func ExportDOT(w io.Writer) error { return defaultRegistry.ExportDOT(w) }
+
+// dotEscaper escapes the characters that are special inside a DOT string.
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// dotID quotes s as a DOT ID.
+func dotID(s string) string { return `"` + dotEscaper.Replace(s) + `"` }
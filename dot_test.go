@@ -0,0 +1,54 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"strings"
+	"testing"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+// dependentPolicy declares dependencies for ExportDOT.
+type dependentPolicy struct {
+	warnPolicy
+	deps []string
+}
+
+func (p dependentPolicy) DependsOn() []string { return p.deps }
+
+func TestExportDOT(t *testing.T) {
+	r := policy.New()
+	r.Register(warnPolicy{id: "quota", prio: 200})
+	r.Register(dependentPolicy{warnPolicy: warnPolicy{id: `say "hi"`, prio: 300}, deps: []string{"quota"}})
+
+	var b strings.Builder
+	if err := r.ExportDOT(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := `digraph ccxpolicy {
+	rankdir=LR;
+	"Evaluate" [shape=doublecircle];
+	"quota" [shape=box];
+	"say \"hi\"" [shape=box];
+	"quota" -> "Evaluate" [label="200"];
+	"say \"hi\"" -> "Evaluate" [label="300"];
+	"say \"hi\"" -> "quota" [style=dashed];
+}
+`
+	if b.String() != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
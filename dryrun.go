@@ -0,0 +1,126 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+import (
+	"errors"
+	"slices"
+)
+
+// ErrNotDryRun is returned by PromotePolicy when the policy is registered but
+// is already fully enforced.
+var ErrNotDryRun = errors.New("ccxpolicy: policy is not in dry-run mode")
+
+// dryRunPolicy wraps a Policy so that every Decision it emits is downgraded
+// to an advisory ActionWarn. Match, ID, and Priority are delegated unchanged,
+// and the wrapped policy's optional interfaces stay visible (see asPolicy).
+type dryRunPolicy struct {
+	Policy
+}
+
+// Check runs the wrapped policy and downgrades its Decisions (see
+// wrapDecisions).
+func (p dryRunPolicy) Check(n Node) []Decision { return p.wrapDecisions(p.Policy.Check(n)) }
+
+// wrapDecisions returns a downgraded copy of ds:
+//   - every Action other than ActionNoop and ActionAudit becomes ActionWarn
+//     (auditing has no effect to stage);
+//   - Adjust and Patch are dropped, as nothing will be mutated;
+//   - Stop is cleared, so a staged policy never hides enforced ones.
+//
+// PolicyID, Scope, and Reason are kept intact for observability. ds itself is
+// not modified, as the wrapped policy may have returned a shared slice.
+func (p dryRunPolicy) wrapDecisions(ds []Decision) []Decision {
+	ds = slices.Clone(ds)
+	for i := range ds {
+		if ds[i].Action != ActionNoop && ds[i].Action != ActionAudit {
+			ds[i].Action = ActionWarn
+		}
+		ds[i].Adjust = nil
+		ds[i].Patch = nil
+		ds[i].Stop = false
+	}
+	return ds
+}
+
+func (p dryRunPolicy) unwrapPolicy() Policy { return p.Policy }
+func (p dryRunPolicy) wrapNode(n Node) Node { return n }
+func (p dryRunPolicy) rewrapPolicy(inner Policy) Policy {
+	p.Policy = inner
+	return p
+}
+
+// RegisterDryRun registers p in dry-run mode: it is evaluated like any other
+// policy, but its Decisions are downgraded to ActionWarn (see dryRunPolicy)
+// until PromotePolicy is called with its ID.
+//
+// This provides a staging pathway for new policies: observe what they would
+// do through Enforcer.Warn before letting them take effect.
+func (r *Registry) RegisterDryRun(p Policy) error {
+	if err := validatePolicy(p); err != nil {
+		return err
+	}
+	r.Register(dryRunPolicy{Policy: p})
+	return nil
+}
+
+// RegisterDryRun registers p in dry-run mode on the global registry.
+func RegisterDryRun(p Policy) error { return defaultRegistry.RegisterDryRun(p) }
+
+// PromotePolicy upgrades the dry-run policy with the given ID to full
+// enforcement. It returns ErrPolicyNotFound if no policy has that ID and
+// ErrNotDryRun if the policy is not in dry-run mode.
+func (r *Registry) PromotePolicy(id string) error {
+	r.mu.Lock()
+	err := ErrPolicyNotFound
+	var promoted Policy
+	for i, p := range r.policies {
+		if p.ID() != id {
+			continue
+		}
+		var ok bool
+		if promoted, ok = stripDryRun(p); !ok {
+			err = ErrNotDryRun
+			continue
+		}
+		r.policies[i] = promoted
+		err = nil
+		break
+	}
+	r.mu.Unlock()
+
+	if err == nil {
+		r.changed(RegistryEvent{Type: PolicyReplaced, PolicyID: id, Policy: promoted})
+	}
+	return err
+}
+
+// stripDryRun returns p with its dry-run wrapper removed, keeping any
+// wrappers around it (e.g. a priority override). ok is false if p is not in
+// dry-run mode.
+func stripDryRun(p Policy) (_ Policy, ok bool) {
+	switch w := p.(type) {
+	case dryRunPolicy:
+		return w.Policy, true
+	case policyWrapper:
+		if inner, ok := stripDryRun(w.unwrapPolicy()); ok {
+			return w.rewrapPolicy(inner), true
+		}
+	}
+	return p, false
+}
+
+// PromotePolicy upgrades a dry-run policy on the global registry.
+func PromotePolicy(id string) error { return defaultRegistry.PromotePolicy(id) }
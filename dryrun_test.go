@@ -0,0 +1,102 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+func TestRegisterDryRunAndPromote(t *testing.T) {
+	r := policy.New()
+	if err := r.RegisterDryRun(policyBStop{}); err != nil {
+		t.Fatalf("RegisterDryRun: %v", err)
+	}
+	r.Register(policyA{})
+
+	n := &testNode{id: "n1", name: "N", params: map[string]any{}}
+	ds := r.Evaluate(n)
+	if len(ds) != 2 {
+		t.Fatalf("dry-run Stop must not short-circuit; got %d decisions", len(ds))
+	}
+	if d := ds[0]; d.PolicyID != "B" || d.Action != policy.ActionWarn || d.Adjust != nil || d.Stop {
+		t.Fatalf("dry-run decision not downgraded: %+v", d)
+	}
+	if ds[0].Reason == nil || ds[0].Reason.Error() != "stop" {
+		t.Fatalf("dry-run reason not preserved: %v", ds[0].Reason)
+	}
+
+	if err := r.PromotePolicy("B"); err != nil {
+		t.Fatalf("PromotePolicy: %v", err)
+	}
+	ds = r.Evaluate(n)
+	if len(ds) != 1 || ds[0].Action != policy.ActionAdjust || !ds[0].Stop {
+		t.Fatalf("promoted policy not enforced: %+v", ds)
+	}
+
+	if err := r.PromotePolicy("B"); !errors.Is(err, policy.ErrNotDryRun) {
+		t.Fatalf("second promote: got %v, want ErrNotDryRun", err)
+	}
+	if err := r.PromotePolicy("missing"); !errors.Is(err, policy.ErrPolicyNotFound) {
+		t.Fatalf("missing promote: got %v, want ErrPolicyNotFound", err)
+	}
+	if err := r.RegisterDryRun(nil); !errors.Is(err, policy.ErrNilPolicy) {
+		t.Fatalf("nil register: got %v, want ErrNilPolicy", err)
+	}
+}
+
+// sharedPolicy returns the same stored slice from every Check.
+type sharedPolicy struct {
+	id string
+	ds []policy.Decision
+}
+
+func (p *sharedPolicy) ID() string                          { return p.id }
+func (p *sharedPolicy) Priority() int                       { return 0 }
+func (p *sharedPolicy) Match(policy.Node) bool              { return true }
+func (p *sharedPolicy) Check(policy.Node) []policy.Decision { return p.ds }
+
+func TestDryRunLeavesPolicyDecisionsIntact(t *testing.T) {
+	shared := &sharedPolicy{id: "shared", ds: []policy.Decision{{
+		PolicyID: "shared",
+		Action:   policy.ActionAdjust,
+		Patch:    []policy.ParamOp{{Op: policy.OpSet, Key: "k", Value: 1}},
+		Stop:     true,
+	}}}
+	r := policy.New()
+	if err := r.RegisterDryRun(shared); err != nil {
+		t.Fatal(err)
+	}
+	ds := r.Evaluate(&testNode{id: "n"})
+	if len(ds) != 1 || ds[0].Action != policy.ActionWarn || ds[0].Patch != nil || ds[0].Stop {
+		t.Fatalf("dry-run decision = %+v", ds)
+	}
+	if d := shared.ds[0]; d.Action != policy.ActionAdjust || d.Patch == nil || !d.Stop {
+		t.Fatalf("policy's stored decision modified: %+v", d)
+	}
+
+	staged := &featurePolicy{warnPolicy: warnPolicy{id: "ctx"}}
+	r = policy.New()
+	if err := r.RegisterDryRun(staged); err != nil {
+		t.Fatal(err)
+	}
+	ds, err := r.EvaluateContext(context.Background(), &testNode{id: "n"})
+	if err != nil || len(ds) != 1 || ds[0].Action != policy.ActionWarn {
+		t.Fatalf("CheckContext bypassed dry-run: %+v, %v", ds, err)
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// decisionJSON is the serialisable form of a Decision. Adjust closures cannot
+// be encoded and are omitted, while a Patch is kept; Reason is reduced to its
+// message.
+type decisionJSON struct {
+	PolicyID string        `json:"policy_id"`
+	Scope    string        `json:"scope"`
+	Action   string        `json:"action"`
+	Reason   string        `json:"reason,omitempty"`
+	Stop     bool          `json:"stop,omitempty"`
+	Patch    []paramOpJSON `json:"patch,omitempty"`
+	Tag      string        `json:"enforcer_tag,omitempty"`
+	// Timestamp is a pointer so that a zero time is omitted.
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+}
+
+// paramOpJSON is the serialisable form of a ParamOp. Value is kept raw so
+// that only an absent value is omitted, never a zero one such as 0 or "".
+type paramOpJSON struct {
+	Op    string          `json:"op"`
+	Key   string          `json:"key"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// toDecisionJSON converts d to its serialisable form. A Value that cannot be
+// encoded is reported as the error json.Marshal returns.
+func toDecisionJSON(d Decision) (decisionJSON, error) {
+	out := decisionJSON{
+		PolicyID: d.PolicyID,
+		Scope:    d.Scope.String(),
+		Action:   d.Action.String(),
+		Stop:     d.Stop,
+		Tag:      d.EnforcerTag,
+	}
+	if d.Reason != nil {
+		out.Reason = d.Reason.Error()
+	}
+	for _, op := range d.Patch {
+		j := paramOpJSON{Op: op.Op.String(), Key: op.Key}
+		if op.Value != nil || op.Op != OpDelete {
+			v, err := json.Marshal(op.Value)
+			if err != nil {
+				return decisionJSON{}, err
+			}
+			j.Value = v
+		}
+		out.Patch = append(out.Patch, j)
+	}
+	if !d.Timestamp.IsZero() {
+		ts := d.Timestamp
+		out.Timestamp = &ts
+	}
+	return out, nil
+}
+
+// MarshalJSON encodes d as a JSON object with the fields policy_id, scope,
+// action, reason, stop, patch, enforcer_tag, and timestamp; scope and action
+// use their String names, and empty optional fields are omitted. The Adjust
+// closure and Metadata are not encoded.
+func (d Decision) MarshalJSON() ([]byte, error) {
+	j, err := toDecisionJSON(d)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON decodes the form written by MarshalJSON. Scope and action
+// names are parsed with ParseScope and ParseAction. The round trip is not
+// lossless: Reason becomes a new error carrying only the encoded message, so
+// errors.Is against the original no longer matches; Patch values decode as
+// encoding/json decodes into any, so an int Value comes back as float64 and
+// a typed map or slice as map[string]any or []any; and Adjust and Metadata
+// are left nil.
+func (d *Decision) UnmarshalJSON(data []byte) error {
+	var j decisionJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	scope, err := ParseScope(j.Scope)
+	if err != nil {
+		return err
+	}
+	action, err := ParseAction(j.Action)
+	if err != nil {
+		return err
+	}
+	out := Decision{
+		PolicyID:    j.PolicyID,
+		Scope:       scope,
+		Action:      action,
+		Stop:        j.Stop,
+		EnforcerTag: j.Tag,
+	}
+	if j.Reason != "" {
+		out.Reason = Reason(j.Reason)
+	}
+	for _, op := range j.Patch {
+		kind, err := ParseParamOpKind(op.Op)
+		if err != nil {
+			return err
+		}
+		var v any
+		if len(op.Value) > 0 {
+			if err := json.Unmarshal(op.Value, &v); err != nil {
+				return err
+			}
+		}
+		out.Patch = append(out.Patch, ParamOp{Op: kind, Key: op.Key, Value: v})
+	}
+	if j.Timestamp != nil {
+		out.Timestamp = *j.Timestamp
+	}
+	*d = out
+	return nil
+}
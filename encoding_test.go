@@ -0,0 +1,95 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+func TestDecisionJSONRoundTrip(t *testing.T) {
+	in := []policy.Decision{
+		{
+			PolicyID:    "cap",
+			Scope:       policy.ScopeSubtree,
+			Action:      policy.ActionAdjust,
+			Adjust:      func(map[string]any) {},
+			Patch:       []policy.ParamOp{{Op: policy.OpSet, Key: "q", Value: 720.0}},
+			Reason:      policy.Reason("too high"),
+			Stop:        true,
+			EnforcerTag: "media",
+			Timestamp:   time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+		{PolicyID: "w", Action: policy.ActionWarn},
+	}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out []policy.Decision
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 2 || out[0].Adjust != nil || out[0].Reason.Error() != "too high" || out[1].Reason != nil {
+		t.Fatalf("decoded %+v", out)
+	}
+	for i := range in {
+		in[i].Adjust, in[i].Reason, out[i].Reason = nil, nil, nil
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Fatalf("round trip:\n got %+v\nwant %+v", out, in)
+	}
+
+	var d policy.Decision
+	if err := json.Unmarshal([]byte(`{"policy_id":"x","scope":"Node","action":"Explode"}`), &d); err == nil {
+		t.Fatal("expected error for unknown action")
+	}
+}
+
+func TestActionScopeText(t *testing.T) {
+	data, err := json.Marshal(map[string]any{"a": policy.ActionCancelRoot, "s": policy.ScopeRoot})
+	if err != nil || string(data) != `{"a":"CancelRoot","s":"Root"}` {
+		t.Fatalf("got %s, %v", data, err)
+	}
+	var v struct {
+		A policy.Action
+		S policy.Scope
+	}
+	if err := json.Unmarshal([]byte(`{"A":"audit","S":"subtree"}`), &v); err != nil || v.A != policy.ActionAudit || v.S != policy.ScopeSubtree {
+		t.Fatalf("got %+v, %v", v, err)
+	}
+}
+
+func TestDecisionJSONKeepsZeroPatchValues(t *testing.T) {
+	in := policy.Decision{PolicyID: "p", Action: policy.ActionAdjust, Patch: []policy.ParamOp{
+		{Op: policy.OpSet, Key: "retries", Value: 0.0},
+		{Op: policy.OpSet, Key: "label", Value: ""},
+	}}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out policy.Decision
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(out.Patch, in.Patch) {
+		t.Fatalf("patch = %#v, want %#v", out.Patch, in.Patch)
+	}
+}
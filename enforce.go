@@ -0,0 +1,461 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// EnforceSorted stable-sorts a copy of ds by the priority of each Decision's
+// originating policy (ascending, as reported by priorityOf(d.PolicyID)) and
+// then applies it with Enforce.
+//
+// Use this when ds was assembled from several sources (e.g., multiple
+// Evaluate calls) and is no longer in priority order. Decisions from the same
+// policy, or from policies with equal priority, keep their relative order.
+// The caller's slice is not modified.
+func EnforceSorted(e Enforcer, ds []Decision, priorityOf func(policyID string) int) {
+	sorted := append([]Decision(nil), ds...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return priorityOf(sorted[i].PolicyID) < priorityOf(sorted[j].PolicyID)
+	})
+	Enforce(e, sorted)
+}
+
+// PanicError reports a panic recovered while running policy or enforcer code.
+// Value holds the recovered value; PolicyID identifies the decision or policy
+// being processed when the panic occurred.
+type PanicError struct {
+	PolicyID string
+	Value    any
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("ccxpolicy: panic in %q: %v", e.PolicyID, e.Value)
+}
+
+// SafeEnforce applies ds like Enforce, but recovers from panics raised by the
+// Enforcer. Each panic is converted to a *PanicError appended to errs, and
+// enforcement continues with the next Decision. applied counts the Decisions
+// processed without panicking. A Decision with Stop == true still ends
+// enforcement, whether or not applying it panicked.
+func SafeEnforce(e Enforcer, ds []Decision) (applied int, errs []error) {
+	for _, d := range ds {
+		if err := safeApply(e, d); err != nil {
+			errs = append(errs, err)
+		} else {
+			applied++
+		}
+		if d.Stop {
+			break
+		}
+	}
+	return applied, errs
+}
+
+// safeApply applies d to e, converting a panic into a *PanicError.
+func safeApply(e Enforcer, d Decision) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = &PanicError{PolicyID: d.PolicyID, Value: v}
+		}
+	}()
+	applyDecision(e, d)
+	return nil
+}
+
+// EnforcerCtx is the context-aware counterpart of Enforcer. Hosts whose
+// effects call downstream APIs (RPCs, databases) implement it so those calls
+// can observe cancellation and deadlines.
+type EnforcerCtx interface {
+	// AdjustCtx applies a parameter mutation function at the specified Scope.
+	AdjustCtx(ctx context.Context, scope Scope, fn func(map[string]any))
+	// CancelCtx aborts work at the specified Scope with a reason suitable for logs.
+	CancelCtx(ctx context.Context, scope Scope, reason error)
+	// WarnCtx records an advisory signal for observability.
+	WarnCtx(ctx context.Context, policyID string, reason error)
+}
+
+// EnforceCtx applies ds to e exactly like Enforce, passing ctx to every call.
+// Before each Decision it checks ctx; if the context is done, enforcement
+// stops and ctx.Err() is returned. It returns nil once all Decisions (or all
+// up to and including a Stop decision) have been applied.
+func EnforceCtx(ctx context.Context, e EnforcerCtx, ds []Decision) error {
+	for _, d := range ds {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		switch d.Action {
+		case ActionWarn:
+			e.WarnCtx(ctx, d.PolicyID, d.Reason)
+		case ActionAdjust:
+			if fn := EffectiveAdjust(d); fn != nil {
+				e.AdjustCtx(ctx, d.Scope, fn)
+			}
+		case ActionCancelNode:
+			e.CancelCtx(ctx, ScopeNode, d.Reason)
+		case ActionCancelSubtree:
+			e.CancelCtx(ctx, ScopeSubtree, d.Reason)
+		case ActionCancelRoot:
+			e.CancelCtx(ctx, ScopeRoot, d.Reason)
+		case ActionAudit:
+			if a, ok := e.(AuditEnforcer); ok {
+				a.Audit(d.PolicyID, d.Scope, d.Reason)
+			}
+		}
+		if d.Stop {
+			return nil
+		}
+	}
+	return nil
+}
+
+// EnforceIdempotent applies ds like Enforce, but skips ActionAdjust decisions
+// that would not change current. Each Adjust is first run against a private
+// copy of current; if the copy is unchanged (reflect.DeepEqual) the decision
+// is skipped, otherwise it is enforced and the copy keeps the change so later
+// decisions are judged against the projected state. current is never mutated.
+//
+// The copy is shallow: an Adjust that mutates a nested map or slice in place
+// would alter current's contents, so such Adjust functions should replace
+// nested values rather than edit them.
+func EnforceIdempotent(e Enforcer, ds []Decision, current map[string]any) {
+	projected := maps.Clone(current)
+	if projected == nil {
+		projected = map[string]any{}
+	}
+	for _, d := range ds {
+		if fn := EffectiveAdjust(d); d.Action == ActionAdjust && fn != nil {
+			next := maps.Clone(projected)
+			fn(next)
+			if reflect.DeepEqual(next, projected) {
+				if d.Stop {
+					return
+				}
+				continue
+			}
+			projected = next
+		}
+		applyDecision(e, d)
+		if d.Stop {
+			return
+		}
+	}
+}
+
+// EnforcerE is the error-reporting counterpart of Enforcer, for hosts whose
+// effects can fail (remote calls, storage writes, ...).
+type EnforcerE interface {
+	// Adjust applies a parameter mutation function at the specified Scope.
+	Adjust(scope Scope, fn func(map[string]any)) error
+	// Cancel aborts work at the specified Scope with a reason suitable for logs.
+	Cancel(scope Scope, reason error) error
+	// Warn records an advisory signal for observability.
+	Warn(policyID string, reason error) error
+}
+
+// applyDecisionE maps a single Decision onto e like applyDecision and returns
+// the error reported by e.
+func applyDecisionE(e EnforcerE, d Decision) error {
+	switch d.Action {
+	case ActionWarn:
+		return e.Warn(d.PolicyID, d.Reason)
+	case ActionAdjust:
+		if fn := EffectiveAdjust(d); fn != nil {
+			return e.Adjust(d.Scope, fn)
+		}
+	case ActionCancelNode:
+		return e.Cancel(ScopeNode, d.Reason)
+	case ActionCancelSubtree:
+		return e.Cancel(ScopeSubtree, d.Reason)
+	case ActionCancelRoot:
+		return e.Cancel(ScopeRoot, d.Reason)
+	case ActionAudit:
+		if a, ok := e.(AuditEnforcer); ok {
+			a.Audit(d.PolicyID, d.Scope, d.Reason)
+		}
+	}
+	return nil
+}
+
+// DecisionError reports an error returned by an EnforcerE while applying
+// Decision.
+type DecisionError struct {
+	Decision Decision
+	Err      error
+}
+
+func (e *DecisionError) Error() string {
+	return fmt.Sprintf("ccxpolicy: enforce %q: %v", e.Decision.PolicyID, e.Err)
+}
+
+// Unwrap returns the underlying enforcer error.
+func (e *DecisionError) Unwrap() error { return e.Err }
+
+// StrictEnforce applies ds to e in order and fails fast: the first error is
+// returned as a *DecisionError and the remaining Decisions are not applied.
+// A Decision with Stop == true ends enforcement as in Enforce.
+func StrictEnforce(e EnforcerE, ds []Decision) error {
+	for _, d := range ds {
+		if err := applyDecisionE(e, d); err != nil {
+			return &DecisionError{Decision: d, Err: err}
+		}
+		if d.Stop {
+			break
+		}
+	}
+	return nil
+}
+
+// EnforceContinueOnError applies ds to e in order and never short-circuits
+// because of an error: every Decision up to and including the first one with
+// Stop == true is attempted, and each failure is collected. Use it when the
+// Decisions are independent, and StrictEnforce when a failure must prevent
+// the remaining effects. The result is nil when every call succeeded.
+func EnforceContinueOnError(e EnforcerE, ds []Decision) []DecisionError {
+	var errs []DecisionError
+	for _, d := range ds {
+		if err := applyDecisionE(e, d); err != nil {
+			errs = append(errs, DecisionError{Decision: d, Err: err})
+		}
+		if d.Stop {
+			break
+		}
+	}
+	return errs
+}
+
+// RetryOptions configures EnforceWithRetry.
+type RetryOptions struct {
+	// MaxAttempts is the total number of calls per Decision, including the
+	// first; values < 1 mean 1 (no retries).
+	MaxAttempts int
+	// InitialDelay is the wait before the first retry.
+	InitialDelay time.Duration
+	// Multiplier scales the delay after every retry; values <= 0 mean 2.
+	Multiplier float64
+	// MaxDelay caps the delay between attempts; 0 means no cap.
+	MaxDelay time.Duration
+}
+
+// EnforceWithRetry applies ds to e like EnforceContinueOnError, but retries a
+// failed call up to opts.MaxAttempts attempts in total, sleeping between
+// attempts with exponential backoff. A Decision that eventually succeeds
+// contributes no error; one that keeps failing contributes the error of its
+// last attempt. A failing Decision with Stop == true is not retried: its
+// first error is recorded and, as always, enforcement ends after it.
+func EnforceWithRetry(e EnforcerE, ds []Decision, opts RetryOptions) []DecisionError {
+	mult := opts.Multiplier
+	if mult <= 0 {
+		mult = 2
+	}
+	var errs []DecisionError
+	for _, d := range ds {
+		err := applyDecisionE(e, d)
+		delay := opts.InitialDelay
+		for attempt := 1; err != nil && !d.Stop && attempt < opts.MaxAttempts; attempt++ {
+			time.Sleep(delay)
+			delay = time.Duration(float64(delay) * mult)
+			if opts.MaxDelay > 0 && delay > opts.MaxDelay {
+				delay = opts.MaxDelay
+			}
+			err = applyDecisionE(e, d)
+		}
+		if err != nil {
+			errs = append(errs, DecisionError{Decision: d, Err: err})
+		}
+		if d.Stop {
+			break
+		}
+	}
+	return errs
+}
+
+// EffectiveScope returns the Scope d applies to: the one implied by the
+// action for the Cancel* actions (as Enforce maps them), and d.Scope
+// otherwise.
+func EffectiveScope(d Decision) Scope {
+	switch d.Action {
+	case ActionCancelNode:
+		return ScopeNode
+	case ActionCancelSubtree:
+		return ScopeSubtree
+	case ActionCancelRoot:
+		return ScopeRoot
+	}
+	return d.Scope
+}
+
+// ScopedDecisions groups ds, evaluated for n, by the ID of the node each
+// Decision must be applied to: n.ID() for ScopeNode and ScopeSubtree (the
+// latter still carries its Scope, marking it for application to the whole
+// subtree), and n.Root().ID() for ScopeRoot (see EffectiveScope). Relative
+// order is preserved within each group. This is the translation layer
+// between policy output and the host's enforcement targets.
+func ScopedDecisions(n Node, ds []Decision) map[string][]Decision {
+	out := map[string][]Decision{}
+	for _, d := range ds {
+		target := n.ID()
+		if EffectiveScope(d) == ScopeRoot {
+			if root := n.Root(); root != nil {
+				target = root.ID()
+			}
+		}
+		out[target] = append(out[target], d)
+	}
+	return out
+}
+
+// CommitEnforcer is an optional interface for batch-oriented Enforcers (e.g.
+// ones buffering database writes) that flush once at the end of a run.
+type CommitEnforcer interface {
+	Commit() error
+}
+
+// CommitEnforce applies ds like Enforce, then calls e.Commit() if e
+// implements CommitEnforcer and returns its error. Enforcers that do not
+// implement it make CommitEnforce equivalent to Enforce returning nil. The
+// wrapping Enforcers of this package (TaggedEnforcer, TeeEnforcer,
+// AttachHistory) forward Commit to the Enforcers they wrap.
+func CommitEnforce(e Enforcer, ds []Decision) error {
+	Enforce(e, ds)
+	return commitEnforcer(e)
+}
+
+// commitEnforcer calls e.Commit() if e implements CommitEnforcer.
+func commitEnforcer(e Enforcer) error {
+	if c, ok := e.(CommitEnforcer); ok {
+		return c.Commit()
+	}
+	return nil
+}
+
+// MetadataNodeSnapshot is the Decision.Metadata key under which
+// EnforceWithNodeSnapshot stores the node snapshot (a Node).
+const MetadataNodeSnapshot = "ccxpolicy.node_snapshot"
+
+// EnforceWithNodeSnapshot applies ds like Enforce after attaching
+// SnapshotNode(n), taken just before enforcement starts, to every Decision's
+// Metadata under MetadataNodeSnapshot. The params may differ from what the
+// policies saw during evaluation; the snapshot records the state that was
+// actually enforced against, for audit. Enforcers read it from the Decision
+// they receive through EnforcerRouter.Route. ds and its Metadata maps are
+// not modified.
+func EnforceWithNodeSnapshot(e Enforcer, ds []Decision, n Node) {
+	snap := SnapshotNode(n)
+	annotated := make([]Decision, len(ds))
+	for i, d := range ds {
+		md := make(map[string]any, len(d.Metadata)+1)
+		for k, v := range d.Metadata {
+			md[k] = v
+		}
+		md[MetadataNodeSnapshot] = snap
+		d.Metadata = md
+		annotated[i] = d
+	}
+	Enforce(e, annotated)
+}
+
+// ComposeAdjust returns one Adjust function that applies every fn against the
+// same original state. Each fn runs on its own shallow copy of the params it
+// is given; the changes it makes (keys set to a new value, keys deleted) are
+// then applied to the params in order, so when several functions change the
+// same key the last one wins, and none of them sees another's result. Values
+// are compared with reflect.DeepEqual.
+func ComposeAdjust(fns ...func(map[string]any)) func(map[string]any) {
+	return func(params map[string]any) {
+		orig := maps.Clone(params)
+		type change struct {
+			set     map[string]any
+			deleted []string
+		}
+		changes := make([]change, 0, len(fns))
+		for _, fn := range fns {
+			m := maps.Clone(orig)
+			if m == nil {
+				m = map[string]any{}
+			}
+			fn(m)
+			var c change
+			for k, v := range m {
+				if old, ok := orig[k]; !ok || !reflect.DeepEqual(old, v) {
+					if c.set == nil {
+						c.set = map[string]any{}
+					}
+					c.set[k] = v
+				}
+			}
+			for k := range orig {
+				if _, ok := m[k]; !ok {
+					c.deleted = append(c.deleted, k)
+				}
+			}
+			changes = append(changes, c)
+		}
+		for _, c := range changes {
+			maps.Copy(params, c.set)
+			for _, k := range c.deleted {
+				delete(params, k)
+			}
+		}
+	}
+}
+
+// CollectAndEnforce applies ds like Enforce, except that the Adjust functions
+// of all ActionAdjust decisions (up to the first Stop) are merged per
+// (Scope, EnforcerTag) with ComposeAdjust, so each sees the original params
+// rather than the previous function's result. Each group gets a single Adjust
+// call, made at the position of its first Adjust decision, which is the
+// Decision passed to an EnforcerRouter; all other decisions are applied in
+// their usual order.
+func CollectAndEnforce(e Enforcer, ds []Decision) {
+	type group struct {
+		scope Scope
+		tag   string
+	}
+	fns := map[group][]func(map[string]any){}
+	for _, d := range ds {
+		if fn := EffectiveAdjust(d); d.Action == ActionAdjust && fn != nil {
+			g := group{d.Scope, d.EnforcerTag}
+			fns[g] = append(fns[g], fn)
+		}
+		if d.Stop {
+			break
+		}
+	}
+	for _, d := range ds {
+		if d.Action == ActionAdjust && EffectiveAdjust(d) != nil {
+			g := group{d.Scope, d.EnforcerTag}
+			merged, pending := fns[g]
+			if !pending {
+				if d.Stop {
+					return
+				}
+				continue
+			}
+			delete(fns, g)
+			d.Adjust, d.Patch = ComposeAdjust(merged...), nil
+		}
+		applyDecision(e, d)
+		if d.Stop {
+			return
+		}
+	}
+}
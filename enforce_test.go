@@ -0,0 +1,370 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+func TestEnforceSortedOrdersByPriority(t *testing.T) {
+	prio := map[string]int{"low": 1, "mid": 5, "high": 9}
+	ds := []policy.Decision{
+		{PolicyID: "high", Action: policy.ActionWarn},
+		{PolicyID: "low", Action: policy.ActionWarn},
+		{PolicyID: "mid", Action: policy.ActionWarn},
+		{PolicyID: "low", Action: policy.ActionWarn, Reason: policy.Reason("second")},
+	}
+	e := &recEnforcer{}
+	policy.EnforceSorted(e, ds, func(id string) int { return prio[id] })
+
+	if want := []string{"low", "low", "mid", "high"}; !reflect.DeepEqual(e.warns, want) {
+		t.Fatalf("warns = %v, want %v", e.warns, want)
+	}
+	if ds[0].PolicyID != "high" {
+		t.Fatalf("input slice was reordered: %+v", ds)
+	}
+}
+
+// panicEnforcer panics on Cancel and records warnings.
+type panicEnforcer struct{ recEnforcer }
+
+func (*panicEnforcer) Cancel(policy.Scope, error) { panic("cancel exploded") }
+
+func TestSafeEnforceRecoversAndContinues(t *testing.T) {
+	e := &panicEnforcer{}
+	ds := []policy.Decision{
+		{PolicyID: "C", Action: policy.ActionCancelNode},
+		{PolicyID: "W", Action: policy.ActionWarn},
+	}
+	applied, errs := policy.SafeEnforce(e, ds)
+
+	if applied != 1 || len(errs) != 1 {
+		t.Fatalf("applied=%d errs=%v", applied, errs)
+	}
+	var pe *policy.PanicError
+	if !errors.As(errs[0], &pe) || pe.PolicyID != "C" || pe.Value != "cancel exploded" {
+		t.Fatalf("unexpected error %#v", errs[0])
+	}
+	if !reflect.DeepEqual(e.warns, []string{"W"}) {
+		t.Fatalf("decision after panic not enforced: %v", e.warns)
+	}
+
+	applied, errs = policy.SafeEnforce(e, []policy.Decision{
+		{PolicyID: "C", Action: policy.ActionCancelRoot, Stop: true},
+		{PolicyID: "W", Action: policy.ActionWarn},
+	})
+	if applied != 0 || len(errs) != 1 || len(e.warns) != 1 {
+		t.Fatalf("Stop not honoured after panic: applied=%d errs=%v warns=%v", applied, errs, e.warns)
+	}
+}
+
+// ctxEnforcer records calls and cancels its context after the first one.
+type ctxEnforcer struct {
+	calls  []string
+	cancel context.CancelFunc
+}
+
+func (e *ctxEnforcer) record(kind string) {
+	e.calls = append(e.calls, kind)
+	if e.cancel != nil {
+		e.cancel()
+	}
+}
+
+func (e *ctxEnforcer) AdjustCtx(context.Context, policy.Scope, func(map[string]any)) {
+	e.record("adjust")
+}
+func (e *ctxEnforcer) CancelCtx(context.Context, policy.Scope, error) { e.record("cancel") }
+func (e *ctxEnforcer) WarnCtx(context.Context, string, error)         { e.record("warn") }
+
+func TestEnforceCtx(t *testing.T) {
+	ds := []policy.Decision{
+		{PolicyID: "W", Action: policy.ActionWarn},
+		{PolicyID: "A", Action: policy.ActionAdjust, Adjust: func(map[string]any) {}},
+		{PolicyID: "C", Action: policy.ActionCancelNode},
+	}
+
+	e := &ctxEnforcer{}
+	if err := policy.EnforceCtx(context.Background(), e, ds); err != nil {
+		t.Fatalf("EnforceCtx: %v", err)
+	}
+	if !reflect.DeepEqual(e.calls, []string{"warn", "adjust", "cancel"}) {
+		t.Fatalf("calls = %v", e.calls)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	e = &ctxEnforcer{cancel: cancel}
+	if err := policy.EnforceCtx(ctx, e, ds); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if len(e.calls) != 1 {
+		t.Fatalf("enforcement should stop once the context is done: %v", e.calls)
+	}
+}
+
+func TestEnforceIdempotentSkipsNoopAdjusts(t *testing.T) {
+	current := map[string]any{"q": 1080}
+	set := func(v int) func(map[string]any) { return func(m map[string]any) { m["q"] = v } }
+	ds := []policy.Decision{
+		{PolicyID: "same", Action: policy.ActionAdjust, Adjust: set(1080)},
+		{PolicyID: "lower", Action: policy.ActionAdjust, Adjust: set(720)},
+		{PolicyID: "again", Action: policy.ActionAdjust, Adjust: set(720)},
+		{PolicyID: "W", Action: policy.ActionWarn},
+	}
+	e := &recEnforcer{}
+	policy.EnforceIdempotent(e, ds, current)
+
+	if len(e.adjusts) != 1 {
+		t.Fatalf("expected only the changing adjust to be enforced, got %d", len(e.adjusts))
+	}
+	if !reflect.DeepEqual(e.warns, []string{"W"}) {
+		t.Fatalf("non-adjust decisions must pass through: %v", e.warns)
+	}
+	if current["q"] != 1080 {
+		t.Fatalf("current was mutated: %v", current)
+	}
+}
+
+func TestStrictVersusContinueOnError(t *testing.T) {
+	ds := []policy.Decision{
+		{PolicyID: "C1", Action: policy.ActionCancelNode},
+		{PolicyID: "W", Action: policy.ActionWarn},
+		{PolicyID: "C2", Action: policy.ActionCancelSubtree},
+	}
+
+	strict := &memTx{store: &memStore{}, failCancel: true}
+	err := policy.StrictEnforce(strict, ds)
+	var de *policy.DecisionError
+	if !errors.As(err, &de) || de.Decision.PolicyID != "C1" || !errors.Is(err, errCancelFailed) {
+		t.Fatalf("StrictEnforce: %v", err)
+	}
+	if len(strict.staged) != 0 {
+		t.Fatalf("StrictEnforce kept going after the first error: %v", strict.staged)
+	}
+
+	cont := &memTx{store: &memStore{}, failCancel: true}
+	errs := policy.EnforceContinueOnError(cont, ds)
+	if len(errs) != 2 || errs[0].Decision.PolicyID != "C1" || errs[1].Decision.PolicyID != "C2" {
+		t.Fatalf("EnforceContinueOnError errors = %+v", errs)
+	}
+	if want := []string{"W"}; !reflect.DeepEqual(cont.staged, want) {
+		t.Fatalf("applied = %v, want %v", cont.staged, want)
+	}
+}
+
+func TestScopedDecisions(t *testing.T) {
+	root := &testNode{id: "root"}
+	leaf := &testNode{id: "leaf", parent: root}
+	ds := []policy.Decision{
+		{PolicyID: "adjust", Action: policy.ActionAdjust, Scope: policy.ScopeNode},
+		{PolicyID: "kill-root", Action: policy.ActionCancelRoot},
+		{PolicyID: "prune", Action: policy.ActionCancelSubtree},
+		{PolicyID: "root-adjust", Action: policy.ActionAdjust, Scope: policy.ScopeRoot},
+	}
+
+	got := policy.ScopedDecisions(leaf, ds)
+	ids := map[string][]string{}
+	for target, group := range got {
+		ids[target] = decisionIDs(group)
+	}
+	want := map[string][]string{
+		"leaf": {"adjust", "prune"},
+		"root": {"kill-root", "root-adjust"},
+	}
+	if !reflect.DeepEqual(ids, want) {
+		t.Fatalf("groups = %v, want %v", ids, want)
+	}
+	if policy.EffectiveScope(got["leaf"][1]) != policy.ScopeSubtree {
+		t.Fatalf("subtree decision lost its marking: %+v", got["leaf"][1])
+	}
+}
+
+// batchEnforcer buffers warnings until Commit.
+type batchEnforcer struct {
+	recEnforcer
+	flushed []string
+}
+
+func (e *batchEnforcer) Commit() error {
+	e.flushed = append(e.flushed, e.warns...)
+	return nil
+}
+
+func TestCommitEnforce(t *testing.T) {
+	ds := []policy.Decision{
+		{PolicyID: "a", Action: policy.ActionWarn},
+		{PolicyID: "b", Action: policy.ActionWarn},
+	}
+	e := &batchEnforcer{}
+	if err := policy.CommitEnforce(e, ds); err != nil {
+		t.Fatalf("CommitEnforce: %v", err)
+	}
+	if want := []string{"a", "b"}; !reflect.DeepEqual(e.flushed, want) {
+		t.Fatalf("flushed %v, want %v", e.flushed, want)
+	}
+	if err := policy.CommitEnforce(&recEnforcer{}, ds); err != nil {
+		t.Fatalf("plain enforcer: %v", err)
+	}
+}
+
+func TestEnforceWithNodeSnapshot(t *testing.T) {
+	n := &testNode{id: "n", params: map[string]any{"height": 720}}
+	ds := []policy.Decision{{PolicyID: "w", Action: policy.ActionWarn, Metadata: map[string]any{"k": "v"}}}
+	c := &sliceConsumer{}
+
+	policy.EnforceWithNodeSnapshot(policy.AdapterEnforcer(c), ds, n)
+	n.params["height"] = 1080
+
+	if len(c.got) != 1 {
+		t.Fatalf("consumed %+v", c.got)
+	}
+	md := c.got[0].Metadata
+	snap, ok := md[policy.MetadataNodeSnapshot].(policy.Node)
+	if !ok || md["k"] != "v" {
+		t.Fatalf("metadata = %v", md)
+	}
+	if snap.ID() != "n" || snap.Params()["height"] != 720 {
+		t.Fatalf("snapshot = %s %v, want params frozen at enforcement", snap.ID(), snap.Params())
+	}
+	if _, leaked := ds[0].Metadata[policy.MetadataNodeSnapshot]; leaked {
+		t.Fatalf("caller's Metadata was modified")
+	}
+}
+
+// paramEnforcer applies Adjust functions to one params map.
+type paramEnforcer struct {
+	recEnforcer
+	params map[string]any
+}
+
+func (e *paramEnforcer) Adjust(s policy.Scope, fn func(map[string]any)) {
+	e.adjusts = append(e.adjusts, s)
+	fn(e.params)
+}
+
+func TestCollectAndEnforce(t *testing.T) {
+	double := func(m map[string]any) { m["x"] = m["x"].(int) * 2 }
+	inc := func(m map[string]any) { m["x"] = m["x"].(int) + 1 }
+	ds := []policy.Decision{
+		{PolicyID: "double", Action: policy.ActionAdjust, Scope: policy.ScopeSubtree, Adjust: double},
+		{PolicyID: "w", Action: policy.ActionWarn},
+		{PolicyID: "drop", Action: policy.ActionAdjust, Scope: policy.ScopeSubtree, Adjust: func(m map[string]any) { delete(m, "y") }},
+		{PolicyID: "inc", Action: policy.ActionAdjust, Scope: policy.ScopeRoot, Adjust: inc},
+		{PolicyID: "inc2", Action: policy.ActionAdjust, Scope: policy.ScopeSubtree, Adjust: inc, Stop: true},
+		{PolicyID: "late", Action: policy.ActionAdjust, Scope: policy.ScopeNode, Adjust: double},
+	}
+
+	e := &paramEnforcer{params: map[string]any{"x": 10, "y": true}}
+	policy.CollectAndEnforce(e, ds)
+	if want := []policy.Scope{policy.ScopeSubtree, policy.ScopeRoot}; !reflect.DeepEqual(e.adjusts, want) {
+		t.Fatalf("adjust calls %v, want %v", e.adjusts, want)
+	}
+	// Subtree: double and inc2 both saw x=10, inc2 won (11); root: inc on 11.
+	if want := map[string]any{"x": 12}; !reflect.DeepEqual(e.params, want) {
+		t.Fatalf("params %v, want %v", e.params, want)
+	}
+	if want := []string{"w"}; !reflect.DeepEqual(e.warns, want) {
+		t.Fatalf("warns %v, want %v", e.warns, want)
+	}
+}
+
+func TestCollectAndEnforceKeepsEnforcerTags(t *testing.T) {
+	set := func(k string) func(map[string]any) { return func(m map[string]any) { m[k] = true } }
+	a := &paramEnforcer{params: map[string]any{}}
+	b := &paramEnforcer{params: map[string]any{}}
+	e := policy.TaggedEnforcer(map[string]policy.Enforcer{"a": a, "b": b}, &recEnforcer{})
+	policy.CollectAndEnforce(e, []policy.Decision{
+		{PolicyID: "pa", Action: policy.ActionAdjust, Adjust: set("a"), EnforcerTag: "a"},
+		{PolicyID: "pb", Action: policy.ActionAdjust, Adjust: set("b"), EnforcerTag: "b"},
+		{PolicyID: "pa2", Action: policy.ActionAdjust, Adjust: set("a2"), EnforcerTag: "a"},
+	})
+	if want := map[string]any{"a": true, "a2": true}; len(a.adjusts) != 1 || !reflect.DeepEqual(a.params, want) {
+		t.Fatalf("enforcer a: %d calls, params %v", len(a.adjusts), a.params)
+	}
+	if want := map[string]any{"b": true}; len(b.adjusts) != 1 || !reflect.DeepEqual(b.params, want) {
+		t.Fatalf("enforcer b: %d calls, params %v", len(b.adjusts), b.params)
+	}
+}
+
+// flakyEnforcer fails Warn for a policy ID a configured number of times.
+type flakyEnforcer struct {
+	failures map[string]int
+	calls    map[string]int
+}
+
+func (e *flakyEnforcer) Adjust(policy.Scope, func(map[string]any)) error { return nil }
+func (e *flakyEnforcer) Cancel(policy.Scope, error) error                { return nil }
+func (e *flakyEnforcer) Warn(id string, _ error) error {
+	e.calls[id]++
+	if e.failures[id] > 0 {
+		e.failures[id]--
+		return errCancelFailed
+	}
+	return nil
+}
+
+func TestEnforceWithRetry(t *testing.T) {
+	e := &flakyEnforcer{
+		failures: map[string]int{"recovers": 2, "broken": 10, "stop": 1},
+		calls:    map[string]int{},
+	}
+	ds := []policy.Decision{
+		{PolicyID: "recovers", Action: policy.ActionWarn},
+		{PolicyID: "broken", Action: policy.ActionWarn},
+		{PolicyID: "stop", Action: policy.ActionWarn, Stop: true},
+		{PolicyID: "after", Action: policy.ActionWarn},
+	}
+	errs := policy.EnforceWithRetry(e, ds, policy.RetryOptions{
+		MaxAttempts:  3,
+		InitialDelay: time.Microsecond,
+		MaxDelay:     time.Millisecond,
+	})
+	if len(errs) != 2 || errs[0].Decision.PolicyID != "broken" || errs[1].Decision.PolicyID != "stop" {
+		t.Fatalf("errors = %+v", errs)
+	}
+	want := map[string]int{"recovers": 3, "broken": 3, "stop": 1}
+	if !reflect.DeepEqual(e.calls, want) {
+		t.Fatalf("calls = %v, want %v", e.calls, want)
+	}
+}
+
+func TestCommitEnforceThroughWrappers(t *testing.T) {
+	ds := []policy.Decision{{PolicyID: "a", Action: policy.ActionWarn, EnforcerTag: "batch"}}
+	for name, wrap := range map[string]func(policy.Enforcer) policy.Enforcer{
+		"TaggedEnforcer": func(e policy.Enforcer) policy.Enforcer {
+			return policy.TaggedEnforcer(map[string]policy.Enforcer{"batch": e, "alias": e}, nil)
+		},
+		"TeeEnforcer": func(e policy.Enforcer) policy.Enforcer {
+			return policy.TeeEnforcer(e, make(chan policy.Decision, 1))
+		},
+		"AttachHistory": func(e policy.Enforcer) policy.Enforcer {
+			return policy.AttachHistory(e, policy.NewEnforcementHistory(0), "n")
+		},
+	} {
+		e := &batchEnforcer{}
+		if err := policy.CommitEnforce(wrap(e), ds); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if want := []string{"a"}; !reflect.DeepEqual(e.flushed, want) {
+			t.Errorf("%s: flushed %v, want %v", name, e.flushed, want)
+		}
+	}
+}
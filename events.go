@@ -0,0 +1,112 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+// DefaultSubscriberBuffer is the channel capacity used by Subscribe unless
+// overridden via SubscriberBuffer.
+const DefaultSubscriberBuffer = 16
+
+// RegistryEventType classifies a registry mutation.
+type RegistryEventType int
+
+const (
+	// PolicyAdded reports a newly registered policy.
+	PolicyAdded RegistryEventType = iota
+	// PolicyRemoved reports a policy taken out of the registry.
+	PolicyRemoved
+	// PolicyReplaced reports a policy swapped for another with the same ID.
+	PolicyReplaced
+)
+
+func (t RegistryEventType) String() string {
+	switch t {
+	case PolicyAdded:
+		return "Added"
+	case PolicyRemoved:
+		return "Removed"
+	case PolicyReplaced:
+		return "Replaced"
+	}
+	return "RegistryEventType(?)"
+}
+
+// RegistryEvent describes one mutation of a Registry.
+//
+//   - Type:     what happened (Added/Removed/Replaced).
+//   - PolicyID: the affected policy's ID.
+//   - Policy:   the policy now registered (Added/Replaced) or the one removed.
+type RegistryEvent struct {
+	Type     RegistryEventType
+	PolicyID string
+	Policy   Policy
+}
+
+// SubscriberBuffer sets the channel capacity of subscriptions created by
+// Registry.Subscribe. Values < 0 are treated as 0 (unbuffered).
+func SubscriberBuffer(size int) RegistryOption {
+	return func(r *Registry) {
+		if size < 0 {
+			size = 0
+		}
+		r.subBuffer = size
+	}
+}
+
+// Subscribe returns a channel that receives a RegistryEvent for every
+// subsequent mutation of r.
+//
+// Delivery never blocks the writer: events are sent without waiting, and an
+// event is dropped for a subscriber whose buffer is full. Size the buffer with
+// SubscriberBuffer and drain the channel promptly. Call Unsubscribe to stop
+// delivery and close the channel.
+func (r *Registry) Subscribe() <-chan RegistryEvent {
+	ch := make(chan RegistryEvent, r.subBuffer)
+
+	r.subMu.Lock()
+	if r.subs == nil {
+		r.subs = map[<-chan RegistryEvent]chan RegistryEvent{}
+	}
+	r.subs[ch] = ch
+	r.subMu.Unlock()
+	return ch
+}
+
+// Unsubscribe stops delivery to ch and closes it. Unknown channels are ignored.
+func (r *Registry) Unsubscribe(ch <-chan RegistryEvent) {
+	r.subMu.Lock()
+	defer r.subMu.Unlock()
+	if c, ok := r.subs[ch]; ok {
+		delete(r.subs, ch)
+		close(c)
+	}
+}
+
+// Subscribe subscribes to mutations of the global registry.
+func Subscribe() <-chan RegistryEvent { return defaultRegistry.Subscribe() }
+
+// Unsubscribe cancels a subscription to the global registry.
+func Unsubscribe(ch <-chan RegistryEvent) { defaultRegistry.Unsubscribe(ch) }
+
+// emit delivers ev to every subscriber without blocking.
+func (r *Registry) emit(ev RegistryEvent) {
+	r.subMu.Lock()
+	defer r.subMu.Unlock()
+	for _, c := range r.subs {
+		select {
+		case c <- ev:
+		default: // subscriber is full; drop rather than stall the writer
+		}
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"testing"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+func TestRegistrySubscribe(t *testing.T) {
+	r := policy.New(policy.SubscriberBuffer(1))
+	ch := r.Subscribe()
+
+	r.Register(warnPolicy{id: "a"})
+	r.Register(warnPolicy{id: "b"}) // buffer full: dropped, must not block
+
+	ev := <-ch
+	if ev.Type != policy.PolicyAdded || ev.PolicyID != "a" || ev.Policy.ID() != "a" {
+		t.Fatalf("unexpected event %+v", ev)
+	}
+	select {
+	case ev := <-ch:
+		t.Fatalf("overflow event should have been dropped, got %+v", ev)
+	default:
+	}
+
+	_ = r.RegisterDryRun(warnPolicy{id: "c"})
+	<-ch
+	if err := r.PromotePolicy("c"); err != nil {
+		t.Fatalf("PromotePolicy: %v", err)
+	}
+	if ev := <-ch; ev.Type != policy.PolicyReplaced || ev.PolicyID != "c" {
+		t.Fatalf("unexpected promote event %+v", ev)
+	}
+
+	r.Unsubscribe(ch)
+	if _, ok := <-ch; ok {
+		t.Fatalf("channel should be closed after Unsubscribe")
+	}
+	r.Register(warnPolicy{id: "d"}) // no subscribers; must not panic
+}
@@ -0,0 +1,244 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// DefaultExplanation is reported for matching policies that do not implement
+// Explainable.
+const DefaultExplanation = "matched (no explanation provided)"
+
+// Explainable is an optional Policy interface describing why the policy
+// matched a node, for operators debugging unexpected decisions.
+type Explainable interface {
+	// Explain returns a human-readable account of why n matched.
+	Explain(n Node) string
+}
+
+// EvaluateWithExplanations returns, for every policy in r whose Match(n) is
+// true, a map entry from policy ID to its Explain(n) output, or
+// DefaultExplanation when the policy is not Explainable. Check is not run,
+// so Stop decisions do not hide lower-priority matches. A panic in Match or
+// Explain is recovered and logged, and the policy's entry is the
+// *PanicError's message.
+func (r *Registry) EvaluateWithExplanations(n Node) map[string]string {
+	out := map[string]string{}
+	for _, p := range r.snapshot() {
+		matched, err := safeMatch(p, n)
+		if err == nil && !matched {
+			continue
+		}
+		var text string
+		if err == nil {
+			text, err = safeExplain(p, n)
+		}
+		if err != nil {
+			r.logPanic(err)
+			text = err.Error()
+		}
+		out[p.ID()] = text
+	}
+	return out
+}
+
+// safeExplain returns p's Explainable account of n, or DefaultExplanation,
+// converting a panic into a *PanicError.
+func safeExplain(p Policy, n Node) (text string, err error) {
+	defer recoverPolicyPanic(p, &err)
+	if ex, n, ok := asPolicyNode[Explainable](p, n); ok {
+		return ex.Explain(n), nil
+	}
+	return DefaultExplanation, nil
+}
+
+// EvaluateWithExplanations runs Registry.EvaluateWithExplanations on the
+// global registry.
+func EvaluateWithExplanations(n Node) map[string]string {
+	return defaultRegistry.EvaluateWithExplanations(n)
+}
+
+// Describable is an optional Policy interface giving a short, static,
+// human-readable description of what the policy does, e.g. for admin
+// endpoints listing the registered policies.
+type Describable interface {
+	// Describe returns a one-line description of the policy.
+	Describe() string
+}
+
+// PolicyDescription returns p.Describe() when p is Describable, and p.ID()
+// otherwise.
+func PolicyDescription(p Policy) string {
+	if d, ok := asPolicy[Describable](p); ok {
+		return d.Describe()
+	}
+	return p.ID()
+}
+
+// MatchingPolicies returns, in evaluation order, the policies visible through
+// r whose Match(n) is true, without running Check. It answers "which policies
+// will run for this node?" for pre-flight debugging. Unlike Evaluate it does
+// not account for Stop, so policies after a stopping one are listed too. A
+// policy whose Match panics is logged and listed, as Evaluate reports it with
+// a warning Decision.
+func (r *Registry) MatchingPolicies(n Node) []Policy {
+	var out []Policy
+	for _, p := range r.snapshot() {
+		matched, err := safeMatch(p, n)
+		if err != nil {
+			r.logPanic(err)
+		}
+		if matched || err != nil {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// MatchingPolicies runs Registry.MatchingPolicies on the global registry.
+func MatchingPolicies(n Node) []Policy { return defaultRegistry.MatchingPolicies(n) }
+
+// Explanation is a trace of one evaluation, produced by Registry.Explain. It
+// renders as text with WriteText or String, and as JSON with encoding/json.
+type Explanation struct {
+	NodeID    string            `json:"node_id"`
+	NodeName  string            `json:"node_name"`
+	Steps     []ExplanationStep `json:"steps"`
+	StoppedBy string            `json:"stopped_by,omitempty"` // "" = no Stop
+	Decisions []Decision        `json:"decisions"`            // as Evaluate returns them
+}
+
+// ExplanationStep records how one policy took part in an evaluation.
+type ExplanationStep struct {
+	PolicyID string  `json:"policy_id"`
+	Priority float64 `json:"priority"` // EffectivePriority
+	Matched  bool    `json:"matched"`
+	// Skipped is true when an earlier Stop meant the policy was not consulted.
+	Skipped bool `json:"skipped,omitempty"`
+	// Explanation is the Explainable output for a matching policy; "" when
+	// the policy is not Explainable.
+	Explanation string     `json:"explanation,omitempty"`
+	Decisions   []Decision `json:"decisions,omitempty"`
+	// Stopped is true when one of Decisions short-circuited evaluation.
+	Stopped bool `json:"stopped,omitempty"`
+	// Error is the message of a panic recovered from Match, Check or Explain.
+	Error string `json:"error,omitempty"`
+}
+
+// Explain evaluates n as EvaluateDetailed does and records, for every policy
+// considered, whether it matched, its Explainable account of the match, the
+// Decisions it returned and whether it stopped evaluation. Middleware is not
+// applied. A panic in Explain is recovered, logged and recorded in the step's
+// Error; the policy's Decisions are kept.
+func (r *Registry) Explain(n Node) *Explanation {
+	pols := r.snapshot()
+	res := r.evaluateDetailed(n, pols)
+	ex := &Explanation{
+		NodeID:    n.ID(),
+		NodeName:  n.Name(),
+		Steps:     make([]ExplanationStep, len(pols)),
+		StoppedBy: res.StoppedBy,
+		Decisions: res.Decisions,
+	}
+	for i, p := range pols {
+		pr := res.Policies[i]
+		st := ExplanationStep{
+			PolicyID:  pr.PolicyID,
+			Priority:  EffectivePriority(p),
+			Matched:   pr.Matched,
+			Skipped:   pr.Skipped,
+			Decisions: pr.Decisions,
+			Stopped:   slices.ContainsFunc(pr.Decisions, func(d Decision) bool { return d.Stop }),
+		}
+		err := pr.Err
+		if _, ok := asPolicy[Explainable](p); ok && pr.Matched && err == nil {
+			if st.Explanation, err = safeExplain(p, n); err != nil {
+				r.logPanic(err)
+			}
+		}
+		if err != nil {
+			st.Error = err.Error()
+		}
+		ex.Steps[i] = st
+	}
+	return ex
+}
+
+// Explain runs Registry.Explain against the global registry.
+func Explain(n Node) *Explanation { return defaultRegistry.Explain(n) }
+
+// String renders ex as WriteText does.
+func (ex *Explanation) String() string {
+	var b strings.Builder
+	_ = ex.WriteText(&b) // strings.Builder never fails
+	return b.String()
+}
+
+// WriteText writes a human-readable trace of ex to w: a header naming the
+// node, then one line per policy in evaluation order, each followed by an
+// indented line per Decision.
+//
+// Example:
+//
+//	node n1 (Transcode): 3 policies, 1 decision(s), stopped by "safety"
+//	- safety [100]: matched (name is Transcode), stop
+//	    CancelRoot Root: safety override
+//	- cap_quality [300]: skipped
+//	- audit [400]: skipped
+func (ex *Explanation) WriteText(w io.Writer) error {
+	stopped := ""
+	if ex.StoppedBy != "" {
+		stopped = fmt.Sprintf(", stopped by %q", ex.StoppedBy)
+	}
+	if _, err := fmt.Fprintf(w, "node %s (%s): %d policies, %d decision(s)%s\n",
+		ex.NodeID, ex.NodeName, len(ex.Steps), len(ex.Decisions), stopped); err != nil {
+		return err
+	}
+	for _, st := range ex.Steps {
+		var outcome string
+		switch {
+		case st.Skipped:
+			outcome = "skipped"
+		case st.Error != "":
+			outcome = "panic: " + st.Error
+		case !st.Matched:
+			outcome = "no match"
+		case st.Explanation != "":
+			outcome = "matched (" + st.Explanation + ")"
+		default:
+			outcome = "matched"
+		}
+		if st.Stopped {
+			outcome += ", stop"
+		}
+		if _, err := fmt.Fprintf(w, "- %s [%g]: %s\n", st.PolicyID, st.Priority, outcome); err != nil {
+			return err
+		}
+		for _, d := range st.Decisions {
+			reason := ""
+			if d.Reason != nil {
+				reason = ": " + d.Reason.Error()
+			}
+			if _, err := fmt.Fprintf(w, "    %s %s%s\n", d.Action, d.Scope, reason); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
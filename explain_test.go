@@ -0,0 +1,159 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+// explainedPolicy explains its match in terms of the node name.
+type explainedPolicy struct{ namedWarnPolicy }
+
+func (p explainedPolicy) Explain(n policy.Node) string { return "name is " + n.Name() }
+
+func TestEvaluateWithExplanations(t *testing.T) {
+	r := policy.New()
+	r.Register(policyBStop{}) // matches everything and stops
+	r.Register(explainedPolicy{namedWarnPolicy{id: "ex", name: "N", prio: 20}})
+	r.Register(namedWarnPolicy{id: "other", name: "Other"})
+
+	got := r.EvaluateWithExplanations(&testNode{id: "n1", name: "N"})
+	want := map[string]string{"B": policy.DefaultExplanation, "ex": "name is N"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("explanations = %v, want %v", got, want)
+	}
+}
+
+// describedPolicy documents itself inline.
+type describedPolicy struct{ warnPolicy }
+
+func (describedPolicy) Describe() string { return "warns on every node" }
+
+func TestPolicyDescription(t *testing.T) {
+	if got := policy.PolicyDescription(describedPolicy{warnPolicy{id: "d"}}); got != "warns on every node" {
+		t.Fatalf("Describable: got %q", got)
+	}
+	if got := policy.PolicyDescription(warnPolicy{id: "plain"}); got != "plain" {
+		t.Fatalf("fallback: got %q", got)
+	}
+}
+
+func TestMatchingPolicies(t *testing.T) {
+	r := policy.New()
+	checked := &countingPolicy{id: "count", name: "job"}
+	r.Register(checked)
+	r.Register(namedWarnPolicy{id: "other", name: "batch", prio: 2})
+	r.Register(warnPolicy{id: "all", prio: 3})
+
+	var ids []string
+	for _, p := range r.MatchingPolicies(&testNode{id: "n", name: "job"}) {
+		ids = append(ids, p.ID())
+	}
+	if want := []string{"count", "all"}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("matching = %v, want %v", ids, want)
+	}
+	if checked.checks != 0 {
+		t.Fatalf("MatchingPolicies ran Check %d times", checked.checks)
+	}
+}
+
+func TestExplain(t *testing.T) {
+	r := policy.New()
+	r.Register(namedWarnPolicy{id: "other", name: "batch", prio: 1})
+	r.Register(explainedPolicy{namedWarnPolicy{id: "why", name: "job", label: "slow", prio: 2}})
+	r.Register(&policy.FuncPolicy{
+		PolicyID: "stop",
+		Prio:     3,
+		MatchFn:  func(policy.Node) bool { return true },
+		CheckFn: func(policy.Node) []policy.Decision {
+			return []policy.Decision{{Scope: policy.ScopeRoot, Action: policy.ActionCancelRoot, Stop: true}}
+		},
+	})
+	r.Register(warnPolicy{id: "late", prio: 4})
+
+	ex := r.Explain(&testNode{id: "n1", name: "job"})
+	want := `node n1 (job): 4 policies, 2 decision(s), stopped by "stop"
+- other [1]: no match
+- why [2]: matched (name is job)
+    Warn Node: slow
+- stop [3]: matched, stop
+    CancelRoot Root
+- late [4]: skipped
+`
+	if got := ex.String(); got != want {
+		t.Fatalf("text:\n%s\nwant:\n%s", got, want)
+	}
+
+	data, err := json.Marshal(ex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var back struct {
+		StoppedBy string `json:"stopped_by"`
+		Steps     []struct {
+			PolicyID string `json:"policy_id"`
+			Matched  bool   `json:"matched"`
+			Skipped  bool   `json:"skipped"`
+			Stopped  bool   `json:"stopped"`
+		} `json:"steps"`
+		Decisions []policy.Decision `json:"decisions"`
+	}
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatal(err)
+	}
+	if back.StoppedBy != "stop" || len(back.Steps) != 4 || !back.Steps[2].Stopped || !back.Steps[3].Skipped || len(back.Decisions) != 2 {
+		t.Fatalf("json: %s", data)
+	}
+}
+
+// panickyExplainer matches every node but panics when asked to explain.
+type panickyExplainer struct{ warnPolicy }
+
+func (panickyExplainer) Explain(policy.Node) string { panic("explain") }
+
+func TestExplanationsRecoverPanics(t *testing.T) {
+	r := policy.New()
+	r.Register(panickyPolicy("match", 1, true))
+	r.Register(panickyExplainer{warnPolicy{id: "explain", prio: 2}})
+	r.Register(warnPolicy{id: "next", prio: 3})
+	n := &testNode{id: "n"}
+
+	got := r.EvaluateWithExplanations(n)
+	if len(got) != 3 || got["next"] != policy.DefaultExplanation ||
+		!strings.Contains(got["match"], "panic") || !strings.Contains(got["explain"], "panic") {
+		t.Fatalf("explanations = %v", got)
+	}
+
+	ex := r.Explain(n)
+	if st := ex.Steps[1]; st.Explanation != "" || !strings.Contains(st.Error, "explain") || len(st.Decisions) != 1 {
+		t.Fatalf("panicking explainer step = %+v", st)
+	}
+	if !strings.Contains(ex.Steps[0].Error, "match") || len(ex.Decisions) != 3 {
+		t.Fatalf("explanation = %+v", ex)
+	}
+
+	var ids []string
+	for _, p := range r.MatchingPolicies(n) {
+		ids = append(ids, p.ID())
+	}
+	if want := []string{"match", "explain", "next"}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("matching = %v, want %v", ids, want)
+	}
+}
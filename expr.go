@@ -0,0 +1,348 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ErrInvalidMatchExpr is wrapped by the errors returned from ParseMatchExpr.
+var ErrInvalidMatchExpr = errors.New("ccxpolicy: invalid match expression")
+
+// ParseMatchExpr compiles a small boolean expression into a MatchFunc, so
+// policies loaded from configuration can declare their Match condition.
+//
+// Grammar:
+//
+//	expr       = and { "||" and }
+//	and        = unary { "&&" unary }
+//	unary      = "!" unary | "(" expr ")" | comparison
+//	comparison = operand op literal
+//	operand    = "name" | "depth" | "param." key
+//	op         = "==" | "!=" | "<" | "<=" | ">" | ">="
+//	literal    = "double-quoted string" | number | true | false
+//
+// Operands:
+//   - name:      n.Name()
+//   - depth:     number of ancestors of n (the root has depth 0)
+//   - param.KEY: n.Params()[KEY]; KEY may itself contain dots
+//     (param.transcode.quality reads the key "transcode.quality")
+//
+// Numbers compare numerically with any Go numeric param type; strings compare
+// lexically; booleans support only == and !=. A comparison against a missing
+// param or a value of a different kind is false.
+//
+// Example:
+//
+//	name == "Transcode" && (param.quality > 1080 || !(depth < 2))
+func ParseMatchExpr(expr string) (MatchFunc, error) {
+	toks, err := lexExpr(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{toks: toks}
+	m, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, p.errorf(t, "unexpected %q", t.text)
+	}
+	return m, nil
+}
+
+type tokKind int
+
+const (
+	tokEOF tokKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokOp // comparison operator
+	tokAnd
+	tokOr
+	tokNot
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind tokKind
+	text string
+	pos  int
+}
+
+// lexExpr splits expr into tokens.
+func lexExpr(expr string) ([]token, error) {
+	var toks []token
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			toks = append(toks, token{tokLParen, "(", i})
+			i++
+		case c == ')':
+			toks = append(toks, token{tokRParen, ")", i})
+			i++
+		case strings.HasPrefix(expr[i:], "&&"):
+			toks = append(toks, token{tokAnd, "&&", i})
+			i += 2
+		case strings.HasPrefix(expr[i:], "||"):
+			toks = append(toks, token{tokOr, "||", i})
+			i += 2
+		case strings.HasPrefix(expr[i:], "=="), strings.HasPrefix(expr[i:], "!="),
+			strings.HasPrefix(expr[i:], "<="), strings.HasPrefix(expr[i:], ">="):
+			toks = append(toks, token{tokOp, expr[i : i+2], i})
+			i += 2
+		case c == '<' || c == '>':
+			toks = append(toks, token{tokOp, expr[i : i+1], i})
+			i++
+		case c == '!':
+			toks = append(toks, token{tokNot, "!", i})
+			i++
+		case c == '"':
+			j := i + 1
+			for j < len(expr) && expr[j] != '"' {
+				if expr[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(expr) {
+				return nil, fmt.Errorf("%w: unterminated string at offset %d", ErrInvalidMatchExpr, i)
+			}
+			s, err := strconv.Unquote(expr[i : j+1])
+			if err != nil {
+				return nil, fmt.Errorf("%w: bad string at offset %d: %v", ErrInvalidMatchExpr, i, err)
+			}
+			toks = append(toks, token{tokString, s, i})
+			i = j + 1
+		case c == '-' || c == '.' || (c >= '0' && c <= '9'):
+			j := i + 1
+			for j < len(expr) && (expr[j] == '.' || (expr[j] >= '0' && expr[j] <= '9') || expr[j] == 'e' || expr[j] == 'E') {
+				j++
+			}
+			toks = append(toks, token{tokNumber, expr[i:j], i})
+			i = j
+		case c == '_' || unicode.IsLetter(rune(c)):
+			j := i + 1
+			for j < len(expr) && (expr[j] == '_' || expr[j] == '.' || expr[j] == '-' ||
+				unicode.IsLetter(rune(expr[j])) || unicode.IsDigit(rune(expr[j]))) {
+				j++
+			}
+			toks = append(toks, token{tokIdent, expr[i:j], i})
+			i = j
+		default:
+			return nil, fmt.Errorf("%w: unexpected character %q at offset %d", ErrInvalidMatchExpr, c, i)
+		}
+	}
+	return append(toks, token{tokEOF, "", len(expr)}), nil
+}
+
+// exprParser is a recursive-descent parser over lexed tokens.
+type exprParser struct {
+	toks []token
+	pos  int
+}
+
+func (p *exprParser) peek() token { return p.toks[p.pos] }
+
+func (p *exprParser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *exprParser) errorf(t token, format string, args ...any) error {
+	return fmt.Errorf("%w: %s at offset %d", ErrInvalidMatchExpr, fmt.Sprintf(format, args...), t.pos)
+}
+
+func (p *exprParser) parseOr() (MatchFunc, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(n Node) bool { return l(n) || right(n) }
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseAnd() (MatchFunc, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokAnd {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(n Node) bool { return l(n) && right(n) }
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseUnary() (MatchFunc, error) {
+	switch t := p.peek(); t.kind {
+	case tokNot:
+		p.next()
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(n Node) bool { return !inner(n) }, nil
+	case tokLParen:
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.kind != tokRParen {
+			return nil, p.errorf(t, "expected ) but found %q", t.text)
+		}
+		return inner, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *exprParser) parseComparison() (MatchFunc, error) {
+	t := p.next()
+	if t.kind != tokIdent {
+		return nil, p.errorf(t, "expected name, depth, or param.KEY but found %q", t.text)
+	}
+	var operand func(Node) (any, bool)
+	switch {
+	case t.text == "name":
+		operand = func(n Node) (any, bool) { return n.Name(), true }
+	case t.text == "depth":
+		operand = func(n Node) (any, bool) { return nodeDepth(n), true }
+	case strings.HasPrefix(t.text, "param.") && len(t.text) > len("param."):
+		key := strings.TrimPrefix(t.text, "param.")
+		operand = func(n Node) (any, bool) {
+			return Param(n, key)
+		}
+	default:
+		return nil, p.errorf(t, "unknown operand %q", t.text)
+	}
+
+	op := p.next()
+	if op.kind != tokOp {
+		return nil, p.errorf(op, "expected comparison operator but found %q", op.text)
+	}
+
+	lit, err := p.parseLiteral()
+	if err != nil {
+		return nil, err
+	}
+	if _, isBool := lit.(bool); isBool && op.text != "==" && op.text != "!=" {
+		return nil, p.errorf(op, "operator %s not supported for booleans", op.text)
+	}
+	return func(n Node) bool {
+		v, ok := operand(n)
+		return ok && compareValues(v, op.text, lit)
+	}, nil
+}
+
+func (p *exprParser) parseLiteral() (any, error) {
+	t := p.next()
+	switch t.kind {
+	case tokString:
+		return t.text, nil
+	case tokNumber:
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, p.errorf(t, "bad number %q", t.text)
+		}
+		return f, nil
+	case tokIdent:
+		switch t.text {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+	}
+	return nil, p.errorf(t, "expected literal but found %q", t.text)
+}
+
+// compareValues applies op to v and the parsed literal lit. Values of a
+// different kind than lit never compare true.
+func compareValues(v any, op string, lit any) bool {
+	switch l := lit.(type) {
+	case float64:
+		f, ok := toFloat64(v)
+		if !ok {
+			return false
+		}
+		return compareOrdered(f, op, l)
+	case string:
+		s, ok := v.(string)
+		if !ok {
+			return false
+		}
+		return compareOrdered(s, op, l)
+	case bool:
+		b, ok := v.(bool)
+		if !ok {
+			return false
+		}
+		return (op == "==") == (b == l)
+	}
+	return false
+}
+
+func compareOrdered[T float64 | string](a T, op string, b T) bool {
+	switch op {
+	case "==":
+		return a == b
+	case "!=":
+		return a != b
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	}
+	return false
+}
+
+// nodeDepth returns the number of ancestors of n.
+func nodeDepth(n Node) int {
+	d := 0
+	for p := n.Parent(); p != nil; p = p.Parent() {
+		d++
+	}
+	return d
+}
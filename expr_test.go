@@ -0,0 +1,78 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"errors"
+	"testing"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+func TestParseMatchExpr(t *testing.T) {
+	root := &testNode{id: "r", name: "Pipeline"}
+	mid := &testNode{id: "m", name: "Stage", parent: root}
+	leaf := &testNode{id: "l", name: "Transcode", parent: mid, params: map[string]any{
+		"quality":        1440,
+		"codec":          "h264",
+		"safety.block":   true,
+		"transcode.rate": 2.5,
+	}}
+
+	cases := []struct {
+		expr string
+		want bool
+	}{
+		{`name == "Transcode"`, true},
+		{`name != "Transcode"`, false},
+		{`param.quality > 1080`, true},
+		{`param.quality <= 1080`, false},
+		{`param.codec == "h264" && depth == 2`, true},
+		{`depth > 2 || param.safety.block == true`, true},
+		{`!(depth >= 2)`, false},
+		{`param.transcode.rate >= 2.5`, true},
+		{`param.missing == 0`, false},
+		{`param.codec > 5`, false},
+		{`name == "X" || name == "Y" && depth == 2`, false},
+		{`(name == "X" || name == "Transcode") && !param.safety.block == false`, true},
+	}
+	for _, c := range cases {
+		m, err := policy.ParseMatchExpr(c.expr)
+		if err != nil {
+			t.Fatalf("ParseMatchExpr(%q): %v", c.expr, err)
+		}
+		if got := m(leaf); got != c.want {
+			t.Fatalf("%q = %v, want %v", c.expr, got, c.want)
+		}
+	}
+}
+
+func TestParseMatchExprErrors(t *testing.T) {
+	for _, expr := range []string{
+		``,
+		`name`,
+		`name ==`,
+		`name == "x`,
+		`color == "red"`,
+		`(depth > 1`,
+		`depth > 1 depth`,
+		`param.flag < true`,
+		`name == "a" @`,
+	} {
+		if _, err := policy.ParseMatchExpr(expr); !errors.Is(err, policy.ErrInvalidMatchExpr) {
+			t.Fatalf("ParseMatchExpr(%q): expected ErrInvalidMatchExpr, got %v", expr, err)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+import "fmt"
+
+// FuncPolicy is a Policy assembled from function values, for small policies
+// that do not warrant a named type. Build it with PolicyFunc, which validates
+// the functions; Register rejects a FuncPolicy whose MatchFn or CheckFn is
+// nil.
+type FuncPolicy struct {
+	PolicyID string
+	Prio     int
+	MatchFn  MatchFunc
+	CheckFn  func(Node) []Decision
+}
+
+// PolicyFunc returns a FuncPolicy with the given fields. It returns an error
+// wrapping ErrNilPolicyFunc if match or check is nil, so configuration
+// mistakes surface at construction rather than on the first evaluation.
+func PolicyFunc(id string, priority int, match MatchFunc, check func(Node) []Decision) (*FuncPolicy, error) {
+	p := &FuncPolicy{PolicyID: id, Prio: priority, MatchFn: match, CheckFn: check}
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *FuncPolicy) ID() string              { return p.PolicyID }
+func (p *FuncPolicy) Priority() int           { return p.Prio }
+func (p *FuncPolicy) Match(n Node) bool       { return p.MatchFn(n) }
+func (p *FuncPolicy) Check(n Node) []Decision { return p.CheckFn(n) }
+
+// validate reports which function of p, if any, is missing.
+func (p *FuncPolicy) validate() error {
+	switch {
+	case p.MatchFn == nil:
+		return fmt.Errorf("%w: %q has no match function", ErrNilPolicyFunc, p.PolicyID)
+	case p.CheckFn == nil:
+		return fmt.Errorf("%w: %q has no check function", ErrNilPolicyFunc, p.PolicyID)
+	}
+	return nil
+}
+
+// validatePolicy returns ErrNilPolicy for a nil p and ErrNilPolicyFunc for a
+// FuncPolicy missing a function.
+func validatePolicy(p Policy) error {
+	if p == nil {
+		return ErrNilPolicy
+	}
+	if fp, ok := p.(*FuncPolicy); ok {
+		if fp == nil {
+			return ErrNilPolicy
+		}
+		return fp.validate()
+	}
+	return nil
+}
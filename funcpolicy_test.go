@@ -0,0 +1,60 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"errors"
+	"testing"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+func TestPolicyFunc(t *testing.T) {
+	p, err := policy.PolicyFunc("fn", 1, policy.MatchByName("job"), func(policy.Node) []policy.Decision {
+		return []policy.Decision{{PolicyID: "fn", Action: policy.ActionWarn}}
+	})
+	if err != nil {
+		t.Fatalf("PolicyFunc: %v", err)
+	}
+	r := policy.New()
+	r.Register(p)
+	if ds := r.Evaluate(&testNode{id: "j", name: "job"}); len(ds) != 1 {
+		t.Fatalf("decisions %+v", ds)
+	}
+
+	if _, err := policy.PolicyFunc("nomatch", 1, nil, p.CheckFn); !errors.Is(err, policy.ErrNilPolicyFunc) {
+		t.Fatalf("nil match: got %v", err)
+	}
+	if _, err := policy.PolicyFunc("nocheck", 1, p.MatchFn, nil); !errors.Is(err, policy.ErrNilPolicyFunc) {
+		t.Fatalf("nil check: got %v", err)
+	}
+}
+
+func TestRegisterRejectsIncompleteFuncPolicy(t *testing.T) {
+	defer func() {
+		err, _ := recover().(error)
+		if !errors.Is(err, policy.ErrNilPolicyFunc) {
+			t.Fatalf("expected ErrNilPolicyFunc panic, got %v", err)
+		}
+	}()
+	policy.New().Register(&policy.FuncPolicy{PolicyID: "broken", MatchFn: policy.MatchByName("x")})
+}
+
+func TestRegisterForTenantRejectsIncompleteFuncPolicy(t *testing.T) {
+	err := policy.RegisterForTenant("funcpolicy", &policy.FuncPolicy{PolicyID: "broken"})
+	if !errors.Is(err, policy.ErrNilPolicyFunc) {
+		t.Fatalf("got %v, want ErrNilPolicyFunc", err)
+	}
+}
@@ -14,4 +14,4 @@
 
 module github.com/ArieDeha/ccxpolicy
 
-go 1.20
+go 1.23
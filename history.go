@@ -0,0 +1,117 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultHistoryLimit is the number of entries EnforcementHistory keeps per
+// node unless configured otherwise.
+const DefaultHistoryLimit = 100
+
+// HistoryEntry is one recorded enforcement event.
+type HistoryEntry struct {
+	Timestamp time.Time
+	PolicyID  string
+	Action    Action
+	Scope     Scope
+}
+
+// EnforcementHistory keeps the most recent enforcement events per node ID in
+// bounded ring buffers. It is safe for concurrent use; the zero value is
+// usable and keeps DefaultHistoryLimit entries per node.
+type EnforcementHistory struct {
+	mu     sync.Mutex
+	limit  int
+	byNode map[string][]HistoryEntry
+}
+
+// NewEnforcementHistory returns a history keeping at most limit entries per
+// node. A limit <= 0 selects DefaultHistoryLimit.
+func NewEnforcementHistory(limit int) *EnforcementHistory {
+	return &EnforcementHistory{limit: limit}
+}
+
+// Record appends one entry per Decision in ds for nodeID, timestamped now,
+// evicting the oldest entries beyond the limit.
+func (h *EnforcementHistory) Record(nodeID string, ds []Decision) {
+	if len(ds) == 0 {
+		return
+	}
+	now := time.Now()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.byNode == nil {
+		h.byNode = map[string][]HistoryEntry{}
+	}
+	limit := h.limit
+	if limit <= 0 {
+		limit = DefaultHistoryLimit
+	}
+	es := h.byNode[nodeID]
+	for _, d := range ds {
+		es = append(es, HistoryEntry{Timestamp: now, PolicyID: d.PolicyID, Action: d.Action, Scope: d.Scope})
+	}
+	if over := len(es) - limit; over > 0 {
+		es = append([]HistoryEntry(nil), es[over:]...)
+	}
+	h.byNode[nodeID] = es
+}
+
+// Recent returns up to n of the newest entries for nodeID, oldest first.
+// n <= 0 returns every retained entry.
+func (h *EnforcementHistory) Recent(nodeID string, n int) []HistoryEntry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	es := h.byNode[nodeID]
+	if n > 0 && n < len(es) {
+		es = es[len(es)-n:]
+	}
+	return append([]HistoryEntry(nil), es...)
+}
+
+// historyEnforcer records each applied Decision before delegating.
+type historyEnforcer struct {
+	inner  Enforcer
+	nodeID string
+	h      *EnforcementHistory
+}
+
+// AttachHistory wraps e so that every Decision processed through Enforce (or
+// any helper honouring EnforcerRouter) is recorded in h under nodeID before
+// being passed to e. Since Enforcers are usually bound to one node, the
+// wrapper is too; create one per node being enforced.
+func AttachHistory(e Enforcer, h *EnforcementHistory, nodeID string) Enforcer {
+	return historyEnforcer{inner: e, nodeID: nodeID, h: h}
+}
+
+// Route implements EnforcerRouter so the full Decision can be recorded.
+func (e historyEnforcer) Route(d Decision) Enforcer {
+	e.h.Record(e.nodeID, []Decision{d})
+	if r, ok := e.inner.(EnforcerRouter); ok {
+		return r.Route(d)
+	}
+	return e.inner
+}
+
+func (e historyEnforcer) Adjust(scope Scope, fn func(map[string]any)) { e.inner.Adjust(scope, fn) }
+func (e historyEnforcer) Cancel(scope Scope, reason error)            { e.inner.Cancel(scope, reason) }
+func (e historyEnforcer) Warn(policyID string, reason error)          { e.inner.Warn(policyID, reason) }
+
+// Commit implements CommitEnforcer by committing inner, if it supports it.
+func (e historyEnforcer) Commit() error { return commitEnforcer(e.inner) }
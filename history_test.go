@@ -0,0 +1,67 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"fmt"
+	"testing"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+func TestEnforcementHistoryBounded(t *testing.T) {
+	h := policy.NewEnforcementHistory(3)
+	for i := 0; i < 5; i++ {
+		h.Record("n1", []policy.Decision{{PolicyID: fmt.Sprint(i), Action: policy.ActionWarn}})
+	}
+	got := h.Recent("n1", 0)
+	if len(got) != 3 || got[0].PolicyID != "2" || got[2].PolicyID != "4" {
+		t.Fatalf("retained entries %+v", got)
+	}
+	if got := h.Recent("n1", 2); len(got) != 2 || got[0].PolicyID != "3" {
+		t.Fatalf("Recent(2) = %+v", got)
+	}
+	if got := h.Recent("other", 5); len(got) != 0 {
+		t.Fatalf("unknown node should have no history: %+v", got)
+	}
+
+	var zero policy.EnforcementHistory
+	zero.Record("n", []policy.Decision{{PolicyID: "z"}})
+	if len(zero.Recent("n", 0)) != 1 {
+		t.Fatalf("zero value should be usable")
+	}
+}
+
+func TestAttachHistory(t *testing.T) {
+	h := policy.NewEnforcementHistory(0)
+	inner := &recEnforcer{}
+	e := policy.AttachHistory(inner, h, "n1")
+
+	policy.Enforce(e, []policy.Decision{
+		{PolicyID: "W", Action: policy.ActionWarn},
+		{PolicyID: "C", Action: policy.ActionCancelSubtree, Scope: policy.ScopeSubtree},
+	})
+
+	if len(inner.warns) != 1 || len(inner.cancels) != 1 {
+		t.Fatalf("inner enforcer not called: %+v", inner)
+	}
+	got := h.Recent("n1", 0)
+	if len(got) != 2 || got[1].PolicyID != "C" || got[1].Action != policy.ActionCancelSubtree || got[1].Scope != policy.ScopeSubtree {
+		t.Fatalf("history %+v", got)
+	}
+	if got[0].Timestamp.IsZero() {
+		t.Fatalf("entries must be timestamped")
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+// Hooks lets observers follow evaluation without wrapping every policy.
+// All fields are optional; nil hooks are skipped. Hooks run synchronously on
+// the evaluating goroutine, so keep them cheap and make them safe for
+// concurrent use if Evaluate is called concurrently.
+type Hooks struct {
+	// OnStop is called when a Decision with Stop == true short-circuits
+	// evaluation. remainingPolicies lists, in priority order, the policies
+	// that were not evaluated as a result (whether or not they would have
+	// matched). It is a fresh slice owned by the hook.
+	OnStop func(stoppingDecision Decision, remainingPolicies []Policy)
+}
+
+// SetHooks replaces the hooks invoked by r's evaluations.
+func (r *Registry) SetHooks(h Hooks) {
+	r.mu.Lock()
+	r.hooks = h
+	r.mu.Unlock()
+}
+
+// SetHooks replaces the hooks invoked by the global registry.
+func SetHooks(h Hooks) { defaultRegistry.SetHooks(h) }
+
+// currentHooks returns r's hooks under the read lock.
+func (r *Registry) currentHooks() Hooks {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.hooks
+}
+
+// DecisionHooks observes enforcement one Decision at a time. Unlike Hooks,
+// which follow a registry's evaluation, DecisionHooks work on any slice of
+// Decisions, whatever its origin (e.g. merged from several evaluations).
+// Both fields are optional.
+type DecisionHooks struct {
+	// Before is called just before d is applied.
+	Before func(d Decision)
+	// After is called once d has been applied. err is a *PanicError if the
+	// Enforcer panicked, nil otherwise.
+	After func(d Decision, err error)
+}
+
+// EnforceWithHooks applies ds like Enforce, calling hooks.Before and
+// hooks.After around every Decision (including the one carrying Stop).
+//
+// If the Enforcer panics, After still runs with a *PanicError, and the panic is
+// then re-raised with its original value so behaviour matches Enforce.
+func EnforceWithHooks(e Enforcer, ds []Decision, hooks DecisionHooks) {
+	for _, d := range ds {
+		if hooks.Before != nil {
+			hooks.Before(d)
+		}
+		err := safeApply(e, d)
+		if hooks.After != nil {
+			hooks.After(d, err)
+		}
+		if pe, ok := err.(*PanicError); ok {
+			panic(pe.Value)
+		}
+		if d.Stop {
+			break
+		}
+	}
+}
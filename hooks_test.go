@@ -0,0 +1,91 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+func TestHooksOnStop(t *testing.T) {
+	r := policy.New()
+	r.Register(policyA{})     // priority 10
+	r.Register(policyBStop{}) // priority 5, stops
+
+	var stopped policy.Decision
+	var remaining []string
+	calls := 0
+	r.SetHooks(policy.Hooks{OnStop: func(d policy.Decision, rest []policy.Policy) {
+		calls++
+		stopped = d
+		for _, p := range rest {
+			remaining = append(remaining, p.ID())
+		}
+	}})
+
+	r.Evaluate(&testNode{id: "n1", name: "N"})
+	if calls != 1 || stopped.PolicyID != "B" {
+		t.Fatalf("OnStop calls=%d decision=%+v", calls, stopped)
+	}
+	if len(remaining) != 1 || remaining[0] != "A" {
+		t.Fatalf("remaining policies = %v", remaining)
+	}
+
+	calls = 0
+	r2 := policy.New()
+	r2.Register(policyA{})
+	r2.SetHooks(policy.Hooks{OnStop: func(policy.Decision, []policy.Policy) { calls++ }})
+	r2.Evaluate(&testNode{id: "n1", name: "N"})
+	if calls != 0 {
+		t.Fatalf("OnStop fired without a Stop decision")
+	}
+}
+
+func TestEnforceWithHooks(t *testing.T) {
+	var trace []string
+	hooks := policy.DecisionHooks{
+		Before: func(d policy.Decision) { trace = append(trace, "before "+d.PolicyID) },
+		After: func(d policy.Decision, err error) {
+			trace = append(trace, fmt.Sprintf("after %s %v", d.PolicyID, err != nil))
+		},
+	}
+	ds := []policy.Decision{
+		{PolicyID: "W", Action: policy.ActionWarn},
+		{PolicyID: "S", Action: policy.ActionWarn, Stop: true},
+		{PolicyID: "X", Action: policy.ActionWarn},
+	}
+	policy.EnforceWithHooks(&recEnforcer{}, ds, hooks)
+
+	want := []string{"before W", "after W false", "before S", "after S false"}
+	if !slices.Equal(trace, want) {
+		t.Fatalf("trace %q, want %q", trace, want)
+	}
+
+	trace = nil
+	func() {
+		defer func() {
+			if v := recover(); v != "cancel exploded" {
+				t.Fatalf("expected original panic to propagate, got %v", v)
+			}
+		}()
+		policy.EnforceWithHooks(&panicEnforcer{}, []policy.Decision{{PolicyID: "C", Action: policy.ActionCancelNode}}, hooks)
+	}()
+	if want := []string{"before C", "after C true"}; !slices.Equal(trace, want) {
+		t.Fatalf("trace %q, want %q", trace, want)
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+import (
+	"sort"
+	"sync"
+)
+
+// ParamGetter is an optional Node interface for reading a single parameter.
+// Nodes implement it when per-key reads are meaningful to them, e.g. to
+// record which keys policies access (see InstrumentedNode).
+type ParamGetter interface {
+	Param(key string) (any, bool)
+}
+
+// Param returns the parameter key of n. It uses n's ParamGetter when available
+// and falls back to n.Params()[key]. Policies that read parameters through
+// Param (as the built-in policies and ParseMatchExpr do) remain observable
+// through InstrumentedNode.
+func Param(n Node, key string) (any, bool) {
+	if g, ok := n.(ParamGetter); ok {
+		return g.Param(key)
+	}
+	v, ok := n.Params()[key]
+	return v, ok
+}
+
+// ParamAccessLog records how policies read a node's parameters. It is safe for
+// concurrent use.
+type ParamAccessLog struct {
+	mu        sync.Mutex
+	keys      map[string]int
+	fullReads int
+	readers   map[string][]string // key -> policy IDs, see AuditingNode
+}
+
+func (l *ParamAccessLog) recordKey(key string) {
+	l.mu.Lock()
+	if l.keys == nil {
+		l.keys = map[string]int{}
+	}
+	l.keys[key]++
+	l.mu.Unlock()
+}
+
+func (l *ParamAccessLog) recordFullRead() {
+	l.mu.Lock()
+	l.fullReads++
+	l.mu.Unlock()
+}
+
+// Count returns how many times key was read through Param.
+func (l *ParamAccessLog) Count(key string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.keys[key]
+}
+
+// Counts returns a copy of the per-key read counts.
+func (l *ParamAccessLog) Counts() map[string]int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out := make(map[string]int, len(l.keys))
+	for k, v := range l.keys {
+		out[k] = v
+	}
+	return out
+}
+
+// FullReads returns how many times the whole map was requested via Params().
+// Such reads cannot be attributed to individual keys.
+func (l *ParamAccessLog) FullReads() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.fullReads
+}
+
+// Unused returns, sorted, the keys of params that were never read through
+// Param. When FullReads() > 0 some of them may still have been inspected via
+// the full map.
+func (l *ParamAccessLog) Unused(params map[string]any) []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var out []string
+	for k := range params {
+		if l.keys[k] == 0 {
+			out = append(out, k)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// InstrumentedNode wraps a Node and records parameter reads in a
+// ParamAccessLog. Per-key reads are captured when policies use Param (or the
+// node's Param method); whole-map Params() calls are counted as full reads.
+type InstrumentedNode struct {
+	Node
+	log *ParamAccessLog
+}
+
+// NewInstrumentedNode wraps n for profiling. Evaluate the returned node, then
+// inspect the log to see which params policies actually use.
+func NewInstrumentedNode(n Node) (*InstrumentedNode, *ParamAccessLog) {
+	log := &ParamAccessLog{}
+	return &InstrumentedNode{Node: n, log: log}, log
+}
+
+// Param implements ParamGetter and records the access. The read is passed
+// on through Param, so a wrapped node's own ParamGetter still sees it.
+func (n *InstrumentedNode) Param(key string) (any, bool) {
+	n.log.recordKey(key)
+	return Param(n.Node, key)
+}
+
+// Params returns the wrapped node's params and counts a full read.
+func (n *InstrumentedNode) Params() map[string]any {
+	n.log.recordFullRead()
+	return n.Node.Params()
+}
+
+// recordReader notes that policyID read key, once per (key, policyID).
+func (l *ParamAccessLog) recordReader(key, policyID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.readers == nil {
+		l.readers = map[string][]string{}
+	}
+	for _, id := range l.readers[key] {
+		if id == policyID {
+			return
+		}
+	}
+	l.readers[key] = append(l.readers[key], policyID)
+}
+
+// Readers returns the IDs of the policies that read key through an
+// AuditingNode, in order of first access. Reads made outside an evaluation
+// are attributed to the empty policy ID.
+func (l *ParamAccessLog) Readers(key string) []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.readers[key]...)
+}
+
+// Accesses returns a copy of the paramKey -> []policyID map recorded by
+// AuditingNode.
+func (l *ParamAccessLog) Accesses() map[string][]string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out := make(map[string][]string, len(l.readers))
+	for k, ids := range l.readers {
+		out[k] = append([]string(nil), ids...)
+	}
+	return out
+}
+
+// policyBinder is implemented by nodes that want to know which policy is
+// reading them. Evaluation hands each policy's Match and Check the node
+// returned by bindNode instead of the node itself.
+type policyBinder interface {
+	forPolicy(policyID string) Node
+}
+
+// bindNode returns n bound to p when n is a policyBinder, and n otherwise.
+func bindNode(n Node, p Policy) Node {
+	if b, ok := n.(policyBinder); ok {
+		return b.forPolicy(p.ID())
+	}
+	return n
+}
+
+// auditingNode attributes parameter reads to the policy it is bound to.
+type auditingNode struct {
+	Node
+	log      *ParamAccessLog
+	policyID string
+}
+
+// AuditingNode wraps n so that every parameter read is recorded in log
+// together with the ID of the policy performing it, for GDPR-style access
+// audits (see ParamAccessLog.Readers and Accesses). Evaluation binds the
+// current policy automatically; no changes to policies are needed.
+//
+// Reads through Param count for the requested key only. A whole-map
+// Params() call counts as a read of every key present, since the policy had
+// access to all of them.
+func AuditingNode(n Node, log *ParamAccessLog) Node {
+	return auditingNode{Node: n, log: log}
+}
+
+func (n auditingNode) forPolicy(policyID string) Node {
+	n.policyID = policyID
+	return n
+}
+
+// Param implements ParamGetter and records the access.
+func (n auditingNode) Param(key string) (any, bool) {
+	n.log.recordKey(key)
+	n.log.recordReader(key, n.policyID)
+	return Param(n.Node, key)
+}
+
+// Params returns the wrapped node's params, recording a read of every key.
+func (n auditingNode) Params() map[string]any {
+	params := n.Node.Params()
+	n.log.recordFullRead()
+	for k := range params {
+		n.log.recordReader(k, n.policyID)
+	}
+	return params
+}
@@ -0,0 +1,95 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"reflect"
+	"testing"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+func TestInstrumentedNode(t *testing.T) {
+	r := policy.New()
+	r.Register(policy.NewParamRangePolicy("range", 1, "quality", 0, 1080, policy.ActionAdjust))
+	r.Register(policyA{}) // never reads params
+
+	n, log := policy.NewInstrumentedNode(&testNode{id: "n1", name: "N", params: map[string]any{
+		"quality": 1440, "codec": "h264", "debug": true,
+	}})
+	ds := r.Evaluate(n)
+	if len(ds) != 2 {
+		t.Fatalf("decisions %+v", ds)
+	}
+
+	if got := log.Count("quality"); got != 2 { // Match + Check
+		t.Fatalf("quality read %d times, want 2", got)
+	}
+	if got := log.Unused(n.Node.Params()); !reflect.DeepEqual(got, []string{"codec", "debug"}) {
+		t.Fatalf("unused = %v", got)
+	}
+	if log.FullReads() != 0 {
+		t.Fatalf("no policy requested the full map, got %d full reads", log.FullReads())
+	}
+
+	_ = n.Params()
+	if log.FullReads() != 1 {
+		t.Fatalf("Params() not counted as a full read")
+	}
+	if v, ok := policy.Param(n, "codec"); !ok || v != "h264" || log.Count("codec") != 1 {
+		t.Fatalf("Param via ParamGetter: v=%v ok=%v count=%d", v, ok, log.Count("codec"))
+	}
+}
+
+func TestInstrumentedNodeDelegatesParam(t *testing.T) {
+	inner, innerLog := policy.NewInstrumentedNode(&testNode{id: "n1", params: map[string]any{"quality": 720}})
+	outer, outerLog := policy.NewInstrumentedNode(inner)
+
+	if v, ok := policy.Param(outer, "quality"); !ok || v != 720 {
+		t.Fatalf("Param = %v, %v", v, ok)
+	}
+	if outerLog.Count("quality") != 1 || innerLog.Count("quality") != 1 {
+		t.Fatalf("reads: outer %d, inner %d; want 1 each", outerLog.Count("quality"), innerLog.Count("quality"))
+	}
+	if innerLog.FullReads() != 0 {
+		t.Fatalf("a keyed read reached the wrapped node as %d full reads", innerLog.FullReads())
+	}
+}
+
+// fullReadPolicy inspects the whole param map.
+type fullReadPolicy struct{ warnPolicy }
+
+func (p fullReadPolicy) Check(n policy.Node) []policy.Decision {
+	_ = n.Params()
+	return nil
+}
+
+func TestAuditingNodeAttributesReadsToPolicies(t *testing.T) {
+	r := policy.New()
+	r.Register(policy.NewParamRangePolicy("bitrate.cap", 1, "bitrate", 0, 5000, policy.ActionWarn))
+	r.Register(fullReadPolicy{warnPolicy{id: "inspector", prio: 2}})
+
+	log := &policy.ParamAccessLog{}
+	n := policy.AuditingNode(&testNode{id: "n", params: map[string]any{"bitrate": 9000, "email": "a@b.c"}}, log)
+	r.Evaluate(n)
+
+	want := map[string][]string{
+		"bitrate": {"bitrate.cap", "inspector"},
+		"email":   {"inspector"},
+	}
+	if got := log.Accesses(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("accesses = %v, want %v", got, want)
+	}
+}
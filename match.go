@@ -0,0 +1,104 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+import "sync"
+
+// MatchFunc is a reusable Match predicate. Policy implementations can delegate
+// their Match method to one, e.g. `return MatchByName("Transcode")(n)`.
+type MatchFunc func(n Node) bool
+
+// MatchByName returns a MatchFunc that reports whether n.Name() equals one of
+// names.
+//
+// The comparison is exact and uses the raw Name() value. Hosts whose node names
+// vary in case or padding should normalise them first (see NormalizedNode with
+// LowercaseNormalizer/TrimSpaceNormalizer) and register names in the same form.
+func MatchByName(names ...string) MatchFunc {
+	set := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		set[name] = struct{}{}
+	}
+	return func(n Node) bool {
+		_, ok := set[n.Name()]
+		return ok
+	}
+}
+
+// NodeLabels is an optional interface for nodes carrying string labels, in the
+// spirit of Kubernetes labels: routing metadata kept apart from Params, which
+// policies may Adjust. Labels should be treated as read-only.
+type NodeLabels interface {
+	Labels() map[string]string
+}
+
+// MatchByLabel returns a MatchFunc that reports whether n implements
+// NodeLabels and has label key set to value.
+func MatchByLabel(key, value string) MatchFunc {
+	return func(n Node) bool {
+		v, ok := nodeLabel(n, key)
+		return ok && v == value
+	}
+}
+
+// MatchByLabelExists returns a MatchFunc that reports whether n implements
+// NodeLabels and has label key set, whatever its value.
+func MatchByLabelExists(key string) MatchFunc {
+	return func(n Node) bool {
+		_, ok := nodeLabel(n, key)
+		return ok
+	}
+}
+
+// nodeLabel looks up label key on n.
+func nodeLabel(n Node, key string) (string, bool) {
+	l, ok := n.(NodeLabels)
+	if !ok {
+		return "", false
+	}
+	v, ok := l.Labels()[key]
+	return v, ok
+}
+
+// compiled caches CompileOnce results by pattern.
+var compiled sync.Map // map[string]*compiledEntry
+
+type compiledEntry struct {
+	once sync.Once
+	v    any
+	err  error
+}
+
+// CompileOnce returns compile(pattern), calling compile at most once per
+// pattern for the life of the process; later calls return the cached value
+// and error. Concurrent first calls for the same pattern wait for a single
+// compilation.
+//
+// Use it inside Match to get lazy compile-once semantics for expensive
+// predicates such as regular expressions:
+//
+//	v, err := ccxpolicy.CompileOnce(`^transcode-\d+$`, func(s string) (any, error) {
+//		return regexp.Compile(s)
+//	})
+//
+// The cache is keyed on pattern alone, so always use the same compile function
+// for a given pattern (namespacing patterns, e.g. "re:"+expr, avoids clashes
+// between unrelated callers). Entries are never evicted.
+func CompileOnce(pattern string, compile func(string) (any, error)) (any, error) {
+	e, _ := compiled.LoadOrStore(pattern, &compiledEntry{})
+	entry := e.(*compiledEntry)
+	entry.once.Do(func() { entry.v, entry.err = compile(pattern) })
+	return entry.v, entry.err
+}
@@ -0,0 +1,94 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+func TestMatchByName(t *testing.T) {
+	m := policy.MatchByName("Transcode", "Upload")
+	for name, want := range map[string]bool{"Transcode": true, "Upload": true, "transcode": false, "": false} {
+		if got := m(&testNode{name: name}); got != want {
+			t.Fatalf("MatchByName(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestCompileOnce(t *testing.T) {
+	var calls atomic.Int32
+	compile := func(s string) (any, error) {
+		calls.Add(1)
+		return regexp.Compile(s)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := policy.CompileOnce(`^compile-once-\d+$`, compile)
+			if err != nil || !v.(*regexp.Regexp).MatchString("compile-once-42") {
+				t.Errorf("CompileOnce = %v, %v", v, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if calls.Load() != 1 {
+		t.Fatalf("compile called %d times, want 1", calls.Load())
+	}
+
+	_, err1 := policy.CompileOnce(`compile-once-(`, compile)
+	_, err2 := policy.CompileOnce(`compile-once-(`, compile)
+	if err1 == nil || err1 != err2 || calls.Load() != 2 {
+		t.Fatalf("errors should be cached too: %v / %v (calls=%d)", err1, err2, calls.Load())
+	}
+}
+
+// labeledNode adds NodeLabels to testNode.
+type labeledNode struct {
+	*testNode
+	labels map[string]string
+}
+
+func (n labeledNode) Labels() map[string]string { return n.labels }
+
+func TestMatchByLabel(t *testing.T) {
+	n := labeledNode{&testNode{id: "n"}, map[string]string{"tier": "gold", "canary": ""}}
+	plain := &testNode{id: "plain"}
+
+	cases := []struct {
+		name string
+		m    policy.MatchFunc
+		n    policy.Node
+		want bool
+	}{
+		{"value match", policy.MatchByLabel("tier", "gold"), n, true},
+		{"value mismatch", policy.MatchByLabel("tier", "silver"), n, false},
+		{"exists with empty value", policy.MatchByLabelExists("canary"), n, true},
+		{"missing key", policy.MatchByLabelExists("region"), n, false},
+		{"node without labels", policy.MatchByLabelExists("tier"), plain, false},
+	}
+	for _, c := range cases {
+		if got := c.m(c.n); got != c.want {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+import (
+	"fmt"
+	"sort"
+)
+
+// MergeRegistries returns a new Registry containing every policy from a and b.
+// It returns an error wrapping ErrDuplicatePolicyID if a policy ID appears in
+// both. The inputs are left untouched; the result inherits a's options, hooks,
+// and middleware.
+func MergeRegistries(a, b *Registry) (*Registry, error) {
+	return mergeRegistries(a, b, false)
+}
+
+// MergeRegistriesAllowOverride is like MergeRegistries, but on an ID conflict
+// the policy from b replaces the one from a instead of failing.
+func MergeRegistriesAllowOverride(a, b *Registry) (*Registry, error) {
+	return mergeRegistries(a, b, true)
+}
+
+func mergeRegistries(a, b *Registry, override bool) (*Registry, error) {
+	pa, pb := a.snapshot(), b.snapshot()
+
+	fromB := make(map[string]struct{}, len(pb))
+	for _, p := range pb {
+		fromB[p.ID()] = struct{}{}
+	}
+
+	merged := make([]Policy, 0, len(pa)+len(pb))
+	for _, p := range pa {
+		if _, clash := fromB[p.ID()]; clash {
+			if !override {
+				return nil, fmt.Errorf("%w: %q", ErrDuplicatePolicyID, p.ID())
+			}
+			continue
+		}
+		merged = append(merged, p)
+	}
+	merged = append(merged, pb...)
+	sort.SliceStable(merged, func(i, j int) bool {
+		return policyLess(merged[i], merged[j])
+	})
+
+	out := a.clone()
+	out.policies = merged
+	out.hooks = a.currentHooks()
+	out.middleware = a.currentMiddleware()
+	return out, nil
+}
+
+// clone returns an empty Registry with the same options as r. Hooks and
+// middleware are not copied.
+func (r *Registry) clone() *Registry {
+	return New(PolicyCheckLimit(r.checkLimit), Logf(r.logf), SubscriberBuffer(r.subBuffer))
+}
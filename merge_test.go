@@ -0,0 +1,101 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"errors"
+	"testing"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+// warnPolicy emits a single warning tagged with its ID and a reason label.
+type warnPolicy struct {
+	id, label string
+	prio      int
+}
+
+func (p warnPolicy) ID() string             { return p.id }
+func (p warnPolicy) Priority() int          { return p.prio }
+func (p warnPolicy) Match(policy.Node) bool { return true }
+func (p warnPolicy) Check(policy.Node) []policy.Decision {
+	return []policy.Decision{{PolicyID: p.id, Action: policy.ActionWarn, Reason: policy.Reason(p.label)}}
+}
+
+func TestMergeRegistries(t *testing.T) {
+	platform := policy.New()
+	platform.Register(warnPolicy{id: "p1", label: "platform", prio: 10})
+	platform.Register(warnPolicy{id: "shared", label: "platform", prio: 30})
+
+	product := policy.New()
+	product.Register(warnPolicy{id: "p2", label: "product", prio: 20})
+
+	merged, err := policy.MergeRegistries(platform, product)
+	if err != nil {
+		t.Fatalf("MergeRegistries: %v", err)
+	}
+	ds := merged.Evaluate(&testNode{id: "n"})
+	var ids []string
+	for _, d := range ds {
+		ids = append(ids, d.PolicyID)
+	}
+	if len(ids) != 3 || ids[0] != "p1" || ids[1] != "p2" || ids[2] != "shared" {
+		t.Fatalf("merged order = %v", ids)
+	}
+
+	product.Register(warnPolicy{id: "shared", label: "product", prio: 5})
+	if _, err := policy.MergeRegistries(platform, product); !errors.Is(err, policy.ErrDuplicatePolicyID) {
+		t.Fatalf("expected ErrDuplicatePolicyID, got %v", err)
+	}
+
+	merged, err = policy.MergeRegistriesAllowOverride(platform, product)
+	if err != nil {
+		t.Fatalf("MergeRegistriesAllowOverride: %v", err)
+	}
+	ds = merged.Evaluate(&testNode{id: "n"})
+	if len(ds) != 3 || ds[0].PolicyID != "shared" || ds[0].Reason.Error() != "product" {
+		t.Fatalf("b should win on conflict: %+v", ds)
+	}
+	if got := len(platform.Evaluate(&testNode{id: "n"})); got != 2 {
+		t.Fatalf("inputs must not be modified; platform has %d decisions", got)
+	}
+}
+
+func TestMergeRegistriesInheritsHooksAndMiddleware(t *testing.T) {
+	a := policy.New()
+	a.Register(&policy.FuncPolicy{
+		PolicyID: "stop",
+		MatchFn:  func(policy.Node) bool { return true },
+		CheckFn: func(policy.Node) []policy.Decision {
+			return []policy.Decision{{Action: policy.ActionCancelNode, Stop: true}}
+		},
+	})
+	stops := 0
+	a.SetHooks(policy.Hooks{OnStop: func(policy.Decision, []policy.Policy) { stops++ }})
+	a.Use(func(n policy.Node, next func(policy.Node) []policy.Decision) []policy.Decision {
+		return append(next(n), policy.Decision{PolicyID: "mw", Action: policy.ActionAudit})
+	})
+	b := policy.New()
+	b.Register(warnPolicy{id: "late", prio: 1})
+
+	merged, err := policy.MergeRegistries(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := merged.Evaluate(&testNode{id: "n"})
+	if len(ds) != 2 || ds[1].PolicyID != "mw" || stops != 1 {
+		t.Fatalf("decisions = %+v, OnStop calls = %d", ds, stops)
+	}
+}
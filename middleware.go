@@ -0,0 +1,56 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+// EvaluateMiddleware wraps a Registry's Evaluate. It receives the node and
+// next, which runs the remaining middleware and then the policies; it may
+// alter the node passed to next, post-process the Decisions, or skip next
+// entirely.
+type EvaluateMiddleware func(n Node, next func(Node) []Decision) []Decision
+
+// Use appends middleware to r. Middleware runs in registration order, the
+// first registered being the outermost, around every Evaluate and
+// EvaluateContext call (and so around CachedEvaluate misses and
+// VersionedEvaluate). It is meant for request-scoped setup and teardown,
+// e.g. wrapping the node so policies see a tenant ID, without changing the
+// Evaluate call sites. EvaluateIter and the other specialised evaluators are
+// not wrapped. Use clears r's decision cache, since the middleware may change
+// what Evaluate returns.
+func (r *Registry) Use(middleware ...EvaluateMiddleware) {
+	r.mu.Lock()
+	r.middleware = append(r.middleware[:len(r.middleware):len(r.middleware)], middleware...)
+	r.mu.Unlock()
+	r.invalidateCache()
+}
+
+// currentMiddleware returns r's middleware, outermost first.
+func (r *Registry) currentMiddleware() []EvaluateMiddleware {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.middleware
+}
+
+// Use appends middleware to the global registry.
+func Use(middleware ...EvaluateMiddleware) { defaultRegistry.Use(middleware...) }
+
+// chainMiddleware composes mw around core, mw[0] outermost.
+func chainMiddleware(mw []EvaluateMiddleware, core func(Node) []Decision) func(Node) []Decision {
+	next := core
+	for i := len(mw) - 1; i >= 0; i-- {
+		m, inner := mw[i], next
+		next = func(n Node) []Decision { return m(n, inner) }
+	}
+	return next
+}
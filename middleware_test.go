@@ -0,0 +1,58 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"slices"
+	"testing"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+func TestUseMiddlewareOrder(t *testing.T) {
+	r := policy.New()
+	r.Register(warnPolicy{id: "p"})
+
+	var calls []string
+	trace := func(name string) policy.EvaluateMiddleware {
+		return func(n policy.Node, next func(policy.Node) []policy.Decision) []policy.Decision {
+			calls = append(calls, name+">")
+			ds := next(n)
+			calls = append(calls, "<"+name)
+			return ds
+		}
+	}
+	r.Use(trace("a"), trace("b"))
+	r.Use(func(n policy.Node, next func(policy.Node) []policy.Decision) []policy.Decision {
+		return append(next(n), policy.Decision{PolicyID: "extra"})
+	})
+
+	ds := r.Evaluate(&testNode{id: "n", name: "Task"})
+	if got := decisionIDs(ds); !slices.Equal(got, []string{"p", "extra"}) {
+		t.Fatalf("decisions = %v", got)
+	}
+	if want := []string{"a>", "b>", "<b", "<a"}; !slices.Equal(calls, want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestUseMiddlewareCanSkipEvaluation(t *testing.T) {
+	r := policy.New()
+	r.Register(warnPolicy{id: "p"})
+	r.Use(func(policy.Node, func(policy.Node) []policy.Decision) []policy.Decision { return nil })
+	if ds := r.Evaluate(&testNode{id: "n"}); len(ds) != 0 {
+		t.Fatalf("expected middleware to short-circuit, got %v", decisionIDs(ds))
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+)
+
+// renamedPolicy serves a policy under a new ID. The wrapped policy's optional
+// interfaces stay visible (see asPolicy).
+type renamedPolicy struct {
+	Policy
+	id, oldID string
+}
+
+func (p renamedPolicy) ID() string { return p.id }
+
+// Check runs the wrapped policy and renames its Decisions (see wrapDecisions).
+func (p renamedPolicy) Check(n Node) []Decision { return p.wrapDecisions(p.Policy.Check(n)) }
+
+// wrapDecisions returns a copy of ds with PolicyID rewritten from the old ID
+// (or an empty one) to the new ID. ds itself is not modified, as the wrapped
+// policy may have returned a shared slice.
+func (p renamedPolicy) wrapDecisions(ds []Decision) []Decision {
+	ds = slices.Clone(ds)
+	for i := range ds {
+		if ds[i].PolicyID == "" || ds[i].PolicyID == p.oldID {
+			ds[i].PolicyID = p.id
+		}
+	}
+	return ds
+}
+
+func (p renamedPolicy) unwrapPolicy() Policy { return p.Policy }
+func (p renamedPolicy) wrapNode(n Node) Node { return n }
+func (p renamedPolicy) rewrapPolicy(inner Policy) Policy {
+	p.Policy = inner
+	return p
+}
+
+// MigratePolicy renames the policy registered in r as oldID to newID. The
+// policy keeps its behaviour and priority, but ID() and the PolicyID of its
+// Decisions become newID. oldID is remembered as an alias (see PolicyAliases),
+// so GetPolicy, Watermark, and other ID lookups still resolve records written
+// before the rename.
+//
+// It returns an error wrapping ErrPolicyNotFound if oldID is not registered
+// in r, and one wrapping ErrDuplicatePolicyID if newID is already in use.
+// Subscribers see PolicyRemoved for oldID followed by PolicyAdded for newID.
+func (r *Registry) MigratePolicy(oldID, newID string) error {
+	r.mu.Lock()
+	i := indexPolicy(r.policies, oldID)
+	switch {
+	case i < 0:
+		r.mu.Unlock()
+		return fmt.Errorf("%w: %q", ErrPolicyNotFound, oldID)
+	case indexPolicy(r.policies, newID) >= 0:
+		r.mu.Unlock()
+		return fmt.Errorf("%w: %q", ErrDuplicatePolicyID, newID)
+	}
+	old := r.policies[i]
+	inner := old
+	if rp, ok := old.(renamedPolicy); ok {
+		inner = rp.Policy
+	}
+	var p Policy = inner // renamed back to its original ID
+	if inner.ID() != newID {
+		p = renamedPolicy{Policy: inner, id: newID, oldID: inner.ID()}
+	}
+	r.policies[i] = p
+	sort.SliceStable(r.policies, func(i, j int) bool {
+		return policyLess(r.policies[i], r.policies[j])
+	})
+	if r.aliases == nil {
+		r.aliases = map[string]string{}
+	}
+	delete(r.aliases, newID)
+	for from, to := range r.aliases {
+		if to == oldID {
+			r.aliases[from] = newID
+		}
+	}
+	r.aliases[oldID] = newID
+	r.mu.Unlock()
+
+	r.changed(
+		RegistryEvent{Type: PolicyRemoved, PolicyID: oldID, Policy: old},
+		RegistryEvent{Type: PolicyAdded, PolicyID: newID, Policy: p},
+	)
+	return nil
+}
+
+// MigratePolicy renames a policy in the global registry.
+func MigratePolicy(oldID, newID string) error { return defaultRegistry.MigratePolicy(oldID, newID) }
+
+// PolicyAliases returns a copy of r's rename history as a map from each former
+// policy ID to the ID it is registered under now.
+func (r *Registry) PolicyAliases() map[string]string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := maps.Clone(r.aliases)
+	if out == nil {
+		out = map[string]string{}
+	}
+	return out
+}
+
+// PolicyAliases returns the global registry's rename history.
+func PolicyAliases() map[string]string { return defaultRegistry.PolicyAliases() }
+
+// GetPolicy returns the policy visible through r under id, resolving former
+// IDs recorded by MigratePolicy.
+func (r *Registry) GetPolicy(id string) (Policy, bool) { return r.lookup(id) }
+
+// GetPolicy looks up a policy in the global registry.
+func GetPolicy(id string) (Policy, bool) { return defaultRegistry.GetPolicy(id) }
@@ -0,0 +1,81 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+func TestMigratePolicy(t *testing.T) {
+	r := policy.New()
+	r.Register(warnPolicy{id: "qcap", prio: 7})
+	r.Register(warnPolicy{id: "other", prio: 8})
+
+	if err := r.MigratePolicy("qcap", "quality.cap"); err != nil {
+		t.Fatalf("MigratePolicy: %v", err)
+	}
+	ds := r.Evaluate(&testNode{id: "n"})
+	if got := decisionIDs(ds); !reflect.DeepEqual(got, []string{"quality.cap", "other"}) {
+		t.Fatalf("decisions after rename = %v", got)
+	}
+	for _, id := range []string{"qcap", "quality.cap"} {
+		if p, ok := r.GetPolicy(id); !ok || p.ID() != "quality.cap" {
+			t.Fatalf("GetPolicy(%q) = %v, %v", id, p, ok)
+		}
+	}
+	if wm := r.Watermark([]policy.Decision{{PolicyID: "qcap"}}); wm != 7 {
+		t.Fatalf("Watermark of a pre-rename record = %d, want 7", wm)
+	}
+
+	if err := r.MigratePolicy("quality.cap", "quality.max"); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"qcap": "quality.max", "quality.cap": "quality.max"}
+	if got := r.PolicyAliases(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("aliases = %v, want %v", got, want)
+	}
+
+	if err := r.MigratePolicy("ghost", "x"); !errors.Is(err, policy.ErrPolicyNotFound) {
+		t.Fatalf("unknown ID: got %v", err)
+	}
+	if err := r.MigratePolicy("quality.max", "other"); !errors.Is(err, policy.ErrDuplicatePolicyID) {
+		t.Fatalf("taken ID: got %v", err)
+	}
+}
+
+func TestMigratedPolicyStaysPromotableAndIntact(t *testing.T) {
+	shared := &sharedPolicy{id: "old", ds: []policy.Decision{{Action: policy.ActionCancelNode}}}
+	r := policy.New()
+	if err := r.RegisterDryRun(shared); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.MigratePolicy("old", "new"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.PromotePolicy("new"); err != nil {
+		t.Fatalf("PromotePolicy after migration: %v", err)
+	}
+	ds := r.Evaluate(&testNode{id: "n"})
+	if len(ds) != 1 || ds[0].PolicyID != "new" || ds[0].Action != policy.ActionCancelNode {
+		t.Fatalf("decisions = %+v", ds)
+	}
+	if shared.ds[0].PolicyID != "" {
+		t.Fatalf("policy's stored decision renamed in place: %+v", shared.ds[0])
+	}
+}
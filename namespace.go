@@ -0,0 +1,84 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+import "slices"
+
+// NamespaceSeparator joins a namespace and a policy ID (e.g., "billing/quota").
+const NamespaceSeparator = "/"
+
+// namespacedPolicy prefixes the wrapped policy's ID with its namespace. The
+// wrapped policy's optional interfaces stay visible (see asPolicy).
+type namespacedPolicy struct {
+	Policy
+	ns string
+}
+
+func (p namespacedPolicy) ID() string { return p.ns + NamespaceSeparator + p.Policy.ID() }
+
+// Check runs the wrapped policy and prefixes its Decisions (see
+// wrapDecisions).
+func (p namespacedPolicy) Check(n Node) []Decision { return p.wrapDecisions(p.Policy.Check(n)) }
+
+// wrapDecisions returns a copy of ds with PolicyID rewritten to the prefixed
+// ID where the policy left it empty or used its unprefixed ID. ds itself is
+// not modified, as the wrapped policy may have returned a shared slice.
+func (p namespacedPolicy) wrapDecisions(ds []Decision) []Decision {
+	ds = slices.Clone(ds)
+	inner := p.Policy.ID()
+	for i := range ds {
+		if ds[i].PolicyID == "" || ds[i].PolicyID == inner {
+			ds[i].PolicyID = p.ID()
+		}
+	}
+	return ds
+}
+
+func (p namespacedPolicy) unwrapPolicy() Policy { return p.Policy }
+func (p namespacedPolicy) wrapNode(n Node) Node { return n }
+func (p namespacedPolicy) rewrapPolicy(inner Policy) Policy {
+	p.Policy = inner
+	return p
+}
+
+// WithNamespace returns register and evaluate functions bound to namespace on
+// the global registry, so independent packages can never collide on IDs.
+//
+//   - register wraps p so its ID (and the PolicyID of its Decisions) becomes
+//     namespace + NamespaceSeparator + p.ID(), then registers it globally. It
+//     returns ErrNilPolicy for a nil p and an error wrapping
+//     ErrDuplicatePolicyID if the prefixed ID is already registered.
+//   - evaluate runs only the policies registered through this namespace, in
+//     the usual priority order. They stay in the namespace when later
+//     wrapped, e.g. by SwapPriorities or MigratePolicy.
+func WithNamespace(namespace string) (register func(Policy) error, evaluate func(Node) []Decision) {
+	register = func(p Policy) error {
+		if err := validatePolicy(p); err != nil {
+			return err
+		}
+		return defaultRegistry.RegisterStrict(namespacedPolicy{Policy: p, ns: namespace})
+	}
+	evaluate = func(n Node) []Decision {
+		out := make([]Decision, 0, 4)
+		for d := range defaultRegistry.evaluateSeq(n, func(p Policy) bool {
+			np, ok := asPolicy[namespacedPolicy](p)
+			return ok && np.ns == namespace
+		}) {
+			out = append(out, d)
+		}
+		return out
+	}
+	return register, evaluate
+}
@@ -0,0 +1,78 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+func TestWithNamespace(t *testing.T) {
+	regBilling, evalBilling := policy.WithNamespace("billing")
+	regVideo, evalVideo := policy.WithNamespace("video")
+
+	if err := regBilling(namedWarnPolicy{id: "quota", name: "namespace-test"}); err != nil {
+		t.Fatalf("billing register: %v", err)
+	}
+	if err := regVideo(namedWarnPolicy{id: "quota", name: "namespace-test"}); err != nil {
+		t.Fatalf("same ID in another namespace must not collide: %v", err)
+	}
+	if err := regVideo(namedWarnPolicy{id: "quota", name: "namespace-test"}); !errors.Is(err, policy.ErrDuplicatePolicyID) {
+		t.Fatalf("duplicate in one namespace: got %v", err)
+	}
+
+	n := &testNode{id: "n1", name: "namespace-test"}
+	ds := evalBilling(n)
+	if len(ds) != 1 || ds[0].PolicyID != "billing/quota" {
+		t.Fatalf("billing decisions %+v", ds)
+	}
+	if ds := evalVideo(n); len(ds) != 1 || ds[0].PolicyID != "video/quota" {
+		t.Fatalf("video decisions %+v", ds)
+	}
+}
+
+func TestWithNamespaceRegistersOnceAndSurvivesWrapping(t *testing.T) {
+	reg, eval := policy.WithNamespace("ns-wrap")
+	defer policy.UnregisterPolicy("ns-wrap/p")
+
+	var ok atomic.Int32
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if reg(namedWarnPolicy{id: "p", name: "namespace-wrap", prio: 1}) == nil {
+				ok.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if ok.Load() != 1 {
+		t.Fatalf("%d concurrent registrations of one ID succeeded", ok.Load())
+	}
+
+	policy.RegisterPolicy(warnPolicy{id: "ns-wrap-other", prio: 2})
+	defer policy.UnregisterPolicy("ns-wrap-other")
+	if err := policy.SwapPriorities("ns-wrap/p", "ns-wrap-other"); err != nil {
+		t.Fatal(err)
+	}
+	if ds := eval(&testNode{id: "n", name: "namespace-wrap"}); len(ds) != 1 || ds[0].PolicyID != "ns-wrap/p" {
+		t.Fatalf("reprioritized policy left its namespace: %+v", ds)
+	}
+}
@@ -0,0 +1,352 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// normalizedNode rewrites Name() through a normalizer. Parent and Root are
+// wrapped as well so lineage-based policies see consistent names.
+type normalizedNode struct {
+	Node
+	normalize func(string) string
+}
+
+// NormalizedNode wraps n so that Name() returns normalizer(n.Name()). All other
+// methods delegate to n; Parent() and Root() return wrapped nodes too.
+// A nil normalizer returns n unchanged.
+func NormalizedNode(n Node, normalizer func(string) string) Node {
+	if n == nil || normalizer == nil {
+		return n
+	}
+	return normalizedNode{Node: n, normalize: normalizer}
+}
+
+func (n normalizedNode) Name() string { return n.normalize(n.Node.Name()) }
+func (n normalizedNode) Parent() Node { return NormalizedNode(n.Node.Parent(), n.normalize) }
+func (n normalizedNode) Root() Node   { return NormalizedNode(n.Node.Root(), n.normalize) }
+
+// LowercaseNormalizer folds a node name to lower case.
+func LowercaseNormalizer(s string) string { return strings.ToLower(s) }
+
+// TrimSpaceNormalizer removes leading and trailing white space from a node name.
+func TrimSpaceNormalizer(s string) string { return strings.TrimSpace(s) }
+
+// VisitPreorder walks the tree rooted at root depth-first in pre-order,
+// calling visit for each node. The Node interface only exposes lineage
+// upwards, so the host supplies children to enumerate a node's descendants.
+//
+//   - visit returning false stops the whole traversal.
+//   - prune returning true for a node skips everything below it; the node
+//     itself is still visited. A nil prune never prunes.
+func VisitPreorder(root Node, children func(Node) []Node, visit func(Node) bool, prune func(Node) bool) {
+	visitPreorder(root, children, visit, prune)
+}
+
+// visitPreorder reports whether traversal should continue.
+func visitPreorder(n Node, children func(Node) []Node, visit func(Node) bool, prune func(Node) bool) bool {
+	if n == nil {
+		return true
+	}
+	if !visit(n) {
+		return false
+	}
+	if prune != nil && prune(n) {
+		return true
+	}
+	for _, c := range children(n) {
+		if !visitPreorder(c, children, visit, prune) {
+			return false
+		}
+	}
+	return true
+}
+
+// NodePath returns the "/"-separated Name() values from the root down to n,
+// e.g. "root/transcoder/segment". It is handy in Reason messages and for log
+// correlation. A nil node yields "".
+func NodePath(n Node) string {
+	return nodePath(n, Node.Name)
+}
+
+// NodePathIDs is like NodePath but joins ID() values instead of names.
+func NodePathIDs(n Node) string {
+	return nodePath(n, Node.ID)
+}
+
+func nodePath(n Node, part func(Node) string) string {
+	var parts []string
+	for cur := n; cur != nil; cur = cur.Parent() {
+		parts = append(parts, part(cur))
+	}
+	for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+		parts[i], parts[j] = parts[j], parts[i]
+	}
+	return strings.Join(parts, "/")
+}
+
+// immutableNode memoizes Match results for a node whose params never change.
+type immutableNode struct {
+	Node
+	mu      sync.Mutex
+	matches map[Policy]bool
+}
+
+// ImmutableNode wraps n, whose Params must not change for the lifetime of the
+// wrapper, so that Evaluate calls each policy's Match at most once for it.
+// Results are cached per policy value inside the returned wrapper, not in any
+// registry, so discarding the wrapper discards the cache. A policy replaced
+// under the same ID is matched afresh, and policies whose dynamic value is not
+// comparable are never cached. This is an explicit opt-in: wrap only nodes
+// that are genuinely immutable, and reuse the wrapper across evaluations.
+//
+// The wrapper forwards n's ParamGetter, NodeLabels and ChildrenNode methods,
+// and policy binding (see AuditingNode): the Match call that fills the cache
+// sees n bound to the policy, as without the wrapper.
+func ImmutableNode(n Node) Node {
+	return &immutableNode{Node: n, matches: map[Policy]bool{}}
+}
+
+// match returns p.Match for the wrapped node, computing it on first use.
+func (n *immutableNode) match(p Policy) bool {
+	if !reflect.ValueOf(p).Comparable() {
+		return p.Match(bindNode(n, p))
+	}
+	n.mu.Lock()
+	m, ok := n.matches[p]
+	n.mu.Unlock()
+	if ok {
+		return m
+	}
+	m = p.Match(bindNode(n, p))
+	n.mu.Lock()
+	n.matches[p] = m
+	n.mu.Unlock()
+	return m
+}
+
+// Param implements ParamGetter by forwarding to the wrapped node.
+func (n *immutableNode) Param(key string) (any, bool) { return Param(n.Node, key) }
+
+// Labels implements NodeLabels; it is nil if the wrapped node has no labels.
+func (n *immutableNode) Labels() map[string]string {
+	if l, ok := n.Node.(NodeLabels); ok {
+		return l.Labels()
+	}
+	return nil
+}
+
+// Children implements ChildrenNode; it is nil if the wrapped node cannot
+// enumerate its children.
+func (n *immutableNode) Children() []Node {
+	if c, ok := n.Node.(ChildrenNode); ok {
+		return c.Children()
+	}
+	return nil
+}
+
+// forPolicy binds the wrapped node to policyID if it supports binding.
+func (n *immutableNode) forPolicy(policyID string) Node {
+	if b, ok := n.Node.(policyBinder); ok {
+		return b.forPolicy(policyID)
+	}
+	return n
+}
+
+// matchPolicy reports p.Match(n), consulting n's cache when n is an
+// ImmutableNode.
+func matchPolicy(p Policy, n Node) bool {
+	if im, ok := n.(*immutableNode); ok {
+		return im.match(p)
+	}
+	return p.Match(bindNode(n, p))
+}
+
+// checkPolicy runs p.Check on n bound to p (see bindNode).
+func checkPolicy(p Policy, n Node) []Decision {
+	return p.Check(bindNode(n, p))
+}
+
+// ChildrenNode is an optional interface for nodes that can enumerate their
+// direct children. ScopedNodeView uses it to see below a node.
+type ChildrenNode interface {
+	Children() []Node
+}
+
+// scopedNodeView exposes the parameters of every node within a Scope.
+type scopedNodeView struct {
+	Node
+	scope Scope
+}
+
+// ScopedNodeView wraps n so that Params() covers exactly what a Decision at
+// scope affects:
+//
+//   - ScopeNode: n.Params(); n itself is returned unchanged.
+//   - ScopeSubtree: n's params merged with those of its descendants.
+//   - ScopeRoot: the merged params of the whole tree under n.Root().
+//
+// Descendants are found through ChildrenNode; a node not implementing it is
+// treated as a leaf. On key collisions the node nearest the top of the view
+// wins (pre-order, first writer). The merged map is a fresh copy built on each
+// Params() call, so mutating it does not touch the underlying nodes.
+func ScopedNodeView(n Node, scope Scope) Node {
+	if n == nil || scope == ScopeNode {
+		return n
+	}
+	return scopedNodeView{Node: n, scope: scope}
+}
+
+func (v scopedNodeView) Params() map[string]any {
+	top := v.Node
+	if v.scope == ScopeRoot {
+		top = v.Node.Root()
+	}
+	merged := map[string]any{}
+	VisitPreorder(top, nodeChildren, func(n Node) bool {
+		for k, val := range n.Params() {
+			if _, ok := merged[k]; !ok {
+				merged[k] = val
+			}
+		}
+		return true
+	}, nil)
+	return merged
+}
+
+// nodeChildren returns n's children when n implements ChildrenNode.
+func nodeChildren(n Node) []Node {
+	if c, ok := n.(ChildrenNode); ok {
+		return c.Children()
+	}
+	return nil
+}
+
+// RedactedValue replaces sensitive parameter values in RedactingNode.
+const RedactedValue = "[REDACTED]"
+
+// redactingNode masks selected parameters. Parent and Root are wrapped as
+// well so lineage-based policies cannot read around the redaction.
+type redactingNode struct {
+	Node
+	keys map[string]struct{}
+}
+
+// RedactingNode wraps n so that Params() returns a copy in which the value of
+// every key listed in redactKeys (when present) is RedactedValue. Other
+// methods delegate to n; Parent() and Root() return redacted nodes too.
+// An empty redactKeys returns n unchanged.
+func RedactingNode(n Node, redactKeys []string) Node {
+	if n == nil || len(redactKeys) == 0 {
+		return n
+	}
+	keys := make(map[string]struct{}, len(redactKeys))
+	for _, k := range redactKeys {
+		keys[k] = struct{}{}
+	}
+	return redactingNode{Node: n, keys: keys}
+}
+
+func (n redactingNode) Params() map[string]any {
+	src := n.Node.Params()
+	if src == nil {
+		return nil
+	}
+	out := make(map[string]any, len(src))
+	for k, v := range src {
+		if _, ok := n.keys[k]; ok {
+			v = RedactedValue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+func (n redactingNode) Parent() Node { return n.wrap(n.Node.Parent()) }
+func (n redactingNode) Root() Node   { return n.wrap(n.Node.Root()) }
+
+// wrap redacts m with n's keys.
+func (n redactingNode) wrap(m Node) Node {
+	if m == nil {
+		return nil
+	}
+	return redactingNode{Node: m, keys: n.keys}
+}
+
+// redactPolicy runs the wrapped policy against redacted nodes.
+type redactPolicy struct {
+	Policy
+	keys []string
+}
+
+// RedactPolicy wraps p so that its Match and Check only ever see
+// RedactingNode(n, keys). ID and Priority are delegated unchanged. Use it to
+// keep credentials and PII away from third-party policies without redacting
+// nodes for the whole registry.
+//
+// The wrapped policy's optional interfaces stay in effect and see redacted
+// nodes too: CheckContext and Explain receive RedactingNode(n, keys). Values
+// the policy copies into a Decision's Metadata under one of keys are
+// redacted as well, so they do not reach enforcers and audit sinks.
+func RedactPolicy(p Policy, keys []string) Policy {
+	return redactPolicy{Policy: p, keys: append([]string(nil), keys...)}
+}
+
+func (p redactPolicy) Match(n Node) bool { return p.Policy.Match(p.wrapNode(n)) }
+func (p redactPolicy) Check(n Node) []Decision {
+	return p.wrapDecisions(p.Policy.Check(p.wrapNode(n)))
+}
+
+func (p redactPolicy) unwrapPolicy() Policy { return p.Policy }
+func (p redactPolicy) wrapNode(n Node) Node { return RedactingNode(n, p.keys) }
+func (p redactPolicy) rewrapPolicy(inner Policy) Policy {
+	p.Policy = inner
+	return p
+}
+
+// wrapDecisions returns ds with the Metadata entries under p's keys set to
+// RedactedValue. Decisions needing no change are shared; the others are
+// copied along with their Metadata.
+func (p redactPolicy) wrapDecisions(ds []Decision) []Decision {
+	var out []Decision
+	for i, d := range ds {
+		var md map[string]any
+		for _, k := range p.keys {
+			if _, ok := d.Metadata[k]; !ok {
+				continue
+			}
+			if md == nil {
+				md = maps.Clone(d.Metadata)
+			}
+			md[k] = RedactedValue
+		}
+		if md == nil {
+			continue
+		}
+		if out == nil {
+			out = slices.Clone(ds)
+		}
+		out[i].Metadata = md
+	}
+	if out == nil {
+		return ds
+	}
+	return out
+}
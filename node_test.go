@@ -0,0 +1,293 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+func TestNormalizedNode(t *testing.T) {
+	root := &testNode{id: "r", name: "  Pipeline "}
+	leaf := &testNode{id: "l", name: " Transcode", parent: root, params: map[string]any{"q": 1}}
+
+	norm := func(s string) string { return policy.LowercaseNormalizer(policy.TrimSpaceNormalizer(s)) }
+	n := policy.NormalizedNode(leaf, norm)
+
+	if got := n.Name(); got != "transcode" {
+		t.Fatalf("Name() = %q", got)
+	}
+	if got := n.Parent().Name(); got != "pipeline" {
+		t.Fatalf("Parent().Name() = %q", got)
+	}
+	if got := n.Root().Name(); got != "pipeline" {
+		t.Fatalf("Root().Name() = %q", got)
+	}
+	if n.ID() != "l" || n.Params()["q"] != 1 {
+		t.Fatalf("ID/Params not delegated")
+	}
+	if n.Parent().Parent() != nil {
+		t.Fatalf("root's parent should stay nil")
+	}
+	if policy.NormalizedNode(leaf, nil) != policy.Node(leaf) {
+		t.Fatalf("nil normalizer should return the node unchanged")
+	}
+
+	if !policy.MatchByName("transcode")(n) || policy.MatchByName("transcode")(leaf) {
+		t.Fatalf("MatchByName should compare the (normalised) raw Name()")
+	}
+}
+
+// testTree builds root -> {a -> {a1, a2}, b -> {b1}} and a children func.
+func testTree() (*testNode, func(policy.Node) []policy.Node) {
+	root := &testNode{id: "root", name: "root"}
+	a := &testNode{id: "a", name: "a", parent: root}
+	b := &testNode{id: "b", name: "b", parent: root}
+	kids := map[string][]policy.Node{
+		"root": {a, b},
+		"a":    {&testNode{id: "a1", name: "a1", parent: a}, &testNode{id: "a2", name: "a2", parent: a}},
+		"b":    {&testNode{id: "b1", name: "b1", parent: b}},
+	}
+	return root, func(n policy.Node) []policy.Node { return kids[n.ID()] }
+}
+
+func TestVisitPreorder(t *testing.T) {
+	root, children := testTree()
+
+	collect := func(prune func(policy.Node) bool, stopAt string) []string {
+		var ids []string
+		policy.VisitPreorder(root, children, func(n policy.Node) bool {
+			ids = append(ids, n.ID())
+			return n.ID() != stopAt
+		}, prune)
+		return ids
+	}
+
+	if got := collect(nil, ""); !reflect.DeepEqual(got, []string{"root", "a", "a1", "a2", "b", "b1"}) {
+		t.Fatalf("pre-order = %v", got)
+	}
+	pruneA := func(n policy.Node) bool { return n.ID() == "a" }
+	if got := collect(pruneA, ""); !reflect.DeepEqual(got, []string{"root", "a", "b", "b1"}) {
+		t.Fatalf("pruned = %v", got)
+	}
+	if got := collect(nil, "a1"); !reflect.DeepEqual(got, []string{"root", "a", "a1"}) {
+		t.Fatalf("stopped = %v", got)
+	}
+}
+
+func TestNodePath(t *testing.T) {
+	root := &testNode{id: "r1", name: "root"}
+	mid := &testNode{id: "t7", name: "transcoder", parent: root}
+	leaf := &testNode{id: "s3", name: "segment", parent: mid}
+
+	if got := policy.NodePath(leaf); got != "root/transcoder/segment" {
+		t.Fatalf("NodePath = %q", got)
+	}
+	if got := policy.NodePathIDs(leaf); got != "r1/t7/s3" {
+		t.Fatalf("NodePathIDs = %q", got)
+	}
+	if got := policy.NodePath(root); got != "root" {
+		t.Fatalf("root NodePath = %q", got)
+	}
+	if got := policy.NodePath(nil); got != "" {
+		t.Fatalf("nil NodePath = %q", got)
+	}
+}
+
+// matchCounter counts Match calls.
+type matchCounter struct {
+	warnPolicy
+	matches int
+}
+
+func (p *matchCounter) Match(policy.Node) bool {
+	p.matches++
+	return true
+}
+
+func TestImmutableNodeCachesMatch(t *testing.T) {
+	p := &matchCounter{warnPolicy: warnPolicy{id: "m"}}
+	r := policy.New()
+	r.Register(p)
+
+	n := policy.ImmutableNode(&testNode{id: "cfg", name: "Config"})
+	for i := 0; i < 3; i++ {
+		if ds := r.Evaluate(n); len(ds) != 1 {
+			t.Fatalf("decisions %+v", ds)
+		}
+	}
+	if p.matches != 1 {
+		t.Fatalf("Match called %d times, want 1", p.matches)
+	}
+
+	r.Evaluate(&testNode{id: "cfg", name: "Config"})
+	if p.matches != 2 {
+		t.Fatalf("plain nodes must not use the cache")
+	}
+	if n.ID() != "cfg" || n.Name() != "Config" {
+		t.Fatalf("wrapper must delegate ID/Name")
+	}
+}
+
+func TestImmutableNodeKeysOnPolicyAndForwards(t *testing.T) {
+	r := policy.New()
+	r.Register(&matchCounter{warnPolicy: warnPolicy{id: "m"}})
+	n := policy.ImmutableNode(&testNode{id: "cfg"})
+	if ds := r.Evaluate(n); len(ds) != 1 {
+		t.Fatalf("decisions %+v", ds)
+	}
+	if err := r.Replace(&policy.FuncPolicy{
+		PolicyID: "m",
+		MatchFn:  func(policy.Node) bool { return false },
+		CheckFn:  func(policy.Node) []policy.Decision { return nil },
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if ds := r.Evaluate(n); len(ds) != 0 {
+		t.Fatalf("stale Match result after Replace: %+v", ds)
+	}
+
+	log := &policy.ParamAccessLog{}
+	audited := policy.ImmutableNode(policy.AuditingNode(&testNode{id: "a", params: map[string]any{"k": 1}}, log))
+	r = policy.New()
+	r.Register(&policy.FuncPolicy{
+		PolicyID: "reader",
+		MatchFn: func(n policy.Node) bool {
+			_, ok := policy.Param(n, "k")
+			return ok
+		},
+		CheckFn: func(policy.Node) []policy.Decision { return nil },
+	})
+	r.Evaluate(audited)
+	if got := log.Readers("k"); len(got) != 1 || got[0] != "reader" {
+		t.Fatalf("Match read not attributed: %v", got)
+	}
+
+	labeled := policy.ImmutableNode(labeledNode{&testNode{id: "l"}, map[string]string{"tier": "gold"}})
+	if !policy.MatchByLabel("tier", "gold")(labeled) {
+		t.Fatal("NodeLabels hidden by ImmutableNode")
+	}
+	tree := policy.ImmutableNode(&treeNode{testNode: &testNode{id: "t"}, kids: []policy.Node{&testNode{id: "c"}}})
+	if c, ok := tree.(policy.ChildrenNode); !ok || len(c.Children()) != 1 {
+		t.Fatal("ChildrenNode hidden by ImmutableNode")
+	}
+	if v, ok := policy.Param(audited, "k"); !ok || v != 1 {
+		t.Fatalf("Param through ImmutableNode = %v, %v", v, ok)
+	}
+}
+
+// treeNode adds ChildrenNode to testNode.
+type treeNode struct {
+	*testNode
+	kids []policy.Node
+}
+
+func (n *treeNode) Children() []policy.Node { return n.kids }
+
+func TestScopedNodeView(t *testing.T) {
+	leaf := &testNode{id: "leaf", params: map[string]any{"bitrate": 500, "codec": "av1"}}
+	mid := &treeNode{
+		testNode: &testNode{id: "mid", params: map[string]any{"bitrate": 1000}},
+		kids:     []policy.Node{leaf},
+	}
+
+	if v := policy.ScopedNodeView(mid, policy.ScopeNode); v != policy.Node(mid) {
+		t.Fatalf("ScopeNode must return the node unchanged")
+	}
+
+	got := policy.ScopedNodeView(mid, policy.ScopeSubtree).Params()
+	want := map[string]any{"bitrate": 1000, "codec": "av1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("subtree params = %v, want %v", got, want)
+	}
+	got["codec"] = "h264"
+	if leaf.params["codec"] != "av1" {
+		t.Fatalf("mutating the view leaked into a descendant")
+	}
+}
+
+// peekPolicy records the params it was shown.
+type peekPolicy struct {
+	warnPolicy
+	seen map[string]any
+}
+
+func (p *peekPolicy) Check(n policy.Node) []policy.Decision {
+	p.seen = n.Params()
+	return nil
+}
+
+func TestRedactingNode(t *testing.T) {
+	root := &testNode{id: "root", params: map[string]any{"token": "root-secret"}}
+	n := &testNode{id: "n", parent: root, params: map[string]any{"token": "s3cret", "user": "ann"}}
+
+	r := policy.RedactingNode(n, []string{"token", "missing"})
+	want := map[string]any{"token": policy.RedactedValue, "user": "ann"}
+	if got := r.Params(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Params() = %v, want %v", got, want)
+	}
+	if got := r.Root().Params()["token"]; got != policy.RedactedValue {
+		t.Fatalf("root token leaked: %v", got)
+	}
+	if n.params["token"] != "s3cret" {
+		t.Fatalf("underlying params were modified")
+	}
+
+	p := &peekPolicy{warnPolicy: warnPolicy{id: "peek"}}
+	reg := policy.New()
+	reg.Register(policy.RedactPolicy(p, []string{"token"}))
+	reg.Evaluate(n)
+	if p.seen["token"] != policy.RedactedValue || p.seen["user"] != "ann" {
+		t.Fatalf("RedactPolicy showed %v", p.seen)
+	}
+}
+
+// ctxPeekPolicy is a ContextPolicy and Explainable that records the params it
+// was shown and copies them into its Decision's Metadata.
+type ctxPeekPolicy struct {
+	peekPolicy
+	explained map[string]any
+}
+
+func (p *ctxPeekPolicy) CheckContext(_ context.Context, n policy.Node) []policy.Decision {
+	p.seen = n.Params()
+	return []policy.Decision{{PolicyID: p.id, Action: policy.ActionAudit, Metadata: n.Params()}}
+}
+
+func (p *ctxPeekPolicy) Explain(n policy.Node) string {
+	p.explained = n.Params()
+	return "peeked"
+}
+
+func TestRedactPolicyCoversContextAndExplain(t *testing.T) {
+	n := &testNode{id: "n", params: map[string]any{"token": "s3cret", "user": "ann"}}
+	p := &ctxPeekPolicy{peekPolicy: peekPolicy{warnPolicy: warnPolicy{id: "peek"}}}
+	reg := policy.New()
+	reg.Register(policy.RedactPolicy(p, []string{"token"}))
+
+	ds, err := reg.EvaluateContext(context.Background(), n)
+	if err != nil || len(ds) != 1 {
+		t.Fatalf("EvaluateContext: %+v, %v", ds, err)
+	}
+	if p.seen["token"] != policy.RedactedValue || ds[0].Metadata["token"] != policy.RedactedValue || ds[0].Metadata["user"] != "ann" {
+		t.Fatalf("CheckContext saw %v, returned metadata %v", p.seen, ds[0].Metadata)
+	}
+	if got := reg.EvaluateWithExplanations(n)["peek"]; got != "peeked" || p.explained["token"] != policy.RedactedValue {
+		t.Fatalf("Explain = %q, saw %v", got, p.explained)
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+import "encoding/json"
+
+// MapNode is a plain in-memory Node with fixed params and explicit children.
+// UnmarshalNodeTree builds trees of MapNodes; tests and fixtures can build
+// them directly with NewMapNode.
+type MapNode struct {
+	id, name string
+	params   map[string]any
+	parent   *MapNode
+	children []*MapNode
+}
+
+// NewMapNode returns a MapNode and makes it the parent of children.
+func NewMapNode(id, name string, params map[string]any, children ...*MapNode) *MapNode {
+	n := &MapNode{id: id, name: name, params: params, children: children}
+	for _, c := range children {
+		c.parent = n
+	}
+	return n
+}
+
+func (n *MapNode) ID() string             { return n.id }
+func (n *MapNode) Name() string           { return n.name }
+func (n *MapNode) Params() map[string]any { return n.params }
+
+func (n *MapNode) Parent() Node {
+	if n.parent == nil {
+		return nil
+	}
+	return n.parent
+}
+
+func (n *MapNode) Root() Node {
+	for n.parent != nil {
+		n = n.parent
+	}
+	return n
+}
+
+// Children implements ChildrenNode.
+func (n *MapNode) Children() []Node {
+	out := make([]Node, len(n.children))
+	for i, c := range n.children {
+		out[i] = c
+	}
+	return out
+}
+
+// nodeTreeJSON is the serialisable form of a node and its descendants.
+type nodeTreeJSON struct {
+	ID       string         `json:"id"`
+	Name     string         `json:"name"`
+	Params   map[string]any `json:"params,omitempty"`
+	Children []nodeTreeJSON `json:"children,omitempty"`
+}
+
+// MarshalNodeTree encodes root and its descendants as JSON: an object with
+// id, name, params, and children, the last being an array of objects of the
+// same shape. children lists a node's direct children; nil means the
+// ChildrenNode interface (nodes not implementing it are leaves). Params must
+// be JSON-encodable. The tree must be finite. A nil root is encoded as null
+// and nil entries returned by children are skipped.
+func MarshalNodeTree(root Node, children func(Node) []Node) ([]byte, error) {
+	if root == nil {
+		return []byte("null"), nil
+	}
+	if children == nil {
+		children = nodeChildren
+	}
+	return json.Marshal(toNodeTreeJSON(root, children))
+}
+
+// toNodeTreeJSON converts n and its descendants to their serialisable form.
+func toNodeTreeJSON(n Node, children func(Node) []Node) nodeTreeJSON {
+	out := nodeTreeJSON{ID: n.ID(), Name: n.Name(), Params: n.Params()}
+	for _, c := range children(n) {
+		if c == nil {
+			continue
+		}
+		out.Children = append(out.Children, toNodeTreeJSON(c, children))
+	}
+	return out
+}
+
+// UnmarshalNodeTree decodes a tree written by MarshalNodeTree and returns its
+// root as a *MapNode. Parent, Root, and Children are wired up; param values
+// follow encoding/json rules (numbers become float64, objects
+// map[string]any). null decodes to a nil Node.
+func UnmarshalNodeTree(data []byte) (Node, error) {
+	var t *nodeTreeJSON
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, nil
+	}
+	return fromNodeTreeJSON(*t), nil
+}
+
+// fromNodeTreeJSON builds the MapNode tree described by t.
+func fromNodeTreeJSON(t nodeTreeJSON) *MapNode {
+	children := make([]*MapNode, len(t.Children))
+	for i, c := range t.Children {
+		children[i] = fromNodeTreeJSON(c)
+	}
+	return NewMapNode(t.ID, t.Name, t.Params, children...)
+}
@@ -0,0 +1,96 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"reflect"
+	"testing"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+func TestNodeTreeRoundTrip(t *testing.T) {
+	leaf := policy.NewMapNode("leaf", "Step", map[string]any{"retries": 3.0})
+	mid := policy.NewMapNode("mid", "Stage", nil, leaf)
+	root := policy.NewMapNode("root", "Job", map[string]any{"owner": "ops"}, mid)
+
+	data, err := policy.MarshalNodeTree(root, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := policy.UnmarshalNodeTree(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID() != "root" || got.Parent() != nil || got.Params()["owner"] != "ops" {
+		t.Fatalf("unexpected root %s %v", got.ID(), got.Params())
+	}
+	kids := got.(policy.ChildrenNode).Children()
+	if len(kids) != 1 || kids[0].Name() != "Stage" {
+		t.Fatalf("unexpected children %v", kids)
+	}
+	l := kids[0].(policy.ChildrenNode).Children()[0]
+	if l.ID() != "leaf" || l.Root().ID() != "root" || l.Parent().ID() != "mid" {
+		t.Fatalf("leaf not wired to its ancestors")
+	}
+	if !reflect.DeepEqual(l.Params(), map[string]any{"retries": 3.0}) {
+		t.Fatalf("leaf params = %v", l.Params())
+	}
+
+	again, err := policy.MarshalNodeTree(got, nil)
+	if err != nil || string(again) != string(data) {
+		t.Fatalf("re-marshal mismatch:\n%s\n%s (%v)", data, again, err)
+	}
+}
+
+func TestMarshalNodeTreeCustomChildren(t *testing.T) {
+	parent := &testNode{id: "p", name: "Job"}
+	child := &testNode{id: "c", name: "Step", parent: parent}
+	data, err := policy.MarshalNodeTree(parent, func(n policy.Node) []policy.Node {
+		if n.ID() == "p" {
+			return []policy.Node{child}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"id":"p","name":"Job","children":[{"id":"c","name":"Step"}]}`; string(data) != want {
+		t.Fatalf("got %s, want %s", data, want)
+	}
+	if _, err := policy.UnmarshalNodeTree([]byte("[")); err == nil {
+		t.Fatal("expected error for malformed JSON")
+	}
+}
+
+func TestMarshalNodeTreeNilRoot(t *testing.T) {
+	data, err := policy.MarshalNodeTree(nil, nil)
+	if err != nil || string(data) != "null" {
+		t.Fatalf("MarshalNodeTree(nil) = %s, %v; want null", data, err)
+	}
+	n, err := policy.UnmarshalNodeTree(data)
+	if err != nil || n != nil {
+		t.Fatalf("UnmarshalNodeTree(null) = %v, %v; want nil", n, err)
+	}
+
+	parent := &testNode{id: "p", name: "Job"}
+	data, err = policy.MarshalNodeTree(parent, func(policy.Node) []policy.Node { return []policy.Node{nil} })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"id":"p","name":"Job"}`; string(data) != want {
+		t.Fatalf("got %s, want %s", data, want)
+	}
+}
@@ -0,0 +1,302 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package policyotel adds OpenTelemetry tracing to ccxpolicy evaluation and
+// enforcement. It lives in its own module so that the core package stays free
+// of third-party dependencies:
+//
+//	import policyotel "github.com/ArieDeha/ccxpolicy/otel"
+package policyotel
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+// Span attribute keys.
+const (
+	AttrPolicyID = attribute.Key("ccxpolicy.policy_id")
+	AttrAction   = attribute.Key("ccxpolicy.action")
+	AttrScope    = attribute.Key("ccxpolicy.scope")
+	AttrReason   = attribute.Key("ccxpolicy.reason")
+	AttrNodeID   = attribute.Key("ccxpolicy.node_id")
+)
+
+// TracingOptions customises the spans emitted by this package. Nil fields
+// fall back to DefaultEvaluateSpanName and DefaultEnforceSpanName.
+type TracingOptions struct {
+	// EvaluateSpanName names the span wrapping one policy's Check.
+	EvaluateSpanName func(policyID string) string
+	// EnforceSpanName names the span wrapping one applied Decision. policyID
+	// is empty for direct Enforcer calls that bypass Enforce.
+	EnforceSpanName func(policyID string, action policy.Action) string
+}
+
+// DefaultEvaluateSpanName returns "ccxpolicy.evaluate <policyID>".
+func DefaultEvaluateSpanName(policyID string) string {
+	return "ccxpolicy.evaluate " + policyID
+}
+
+// DefaultEnforceSpanName returns "ccxpolicy.enforce <Action>".
+func DefaultEnforceSpanName(_ string, action policy.Action) string {
+	return "ccxpolicy.enforce " + action.String()
+}
+
+func (o TracingOptions) evaluateSpanName(policyID string) string {
+	if o.EvaluateSpanName != nil {
+		return o.EvaluateSpanName(policyID)
+	}
+	return DefaultEvaluateSpanName(policyID)
+}
+
+func (o TracingOptions) enforceSpanName(policyID string, action policy.Action) string {
+	if o.EnforceSpanName != nil {
+		return o.EnforceSpanName(policyID, action)
+	}
+	return DefaultEnforceSpanName(policyID, action)
+}
+
+// TracingEnforcer wraps inner so that every applied Decision is recorded as a
+// span from tracer, using the default span names.
+func TracingEnforcer(inner policy.Enforcer, tracer trace.Tracer) policy.Enforcer {
+	return TracingEnforcerWithOptions(inner, tracer, TracingOptions{})
+}
+
+// TracingEnforcerWithOptions is TracingEnforcer with customised span names.
+//
+// Decisions processed through Enforce (or another helper honouring
+// policy.EnforcerRouter) produce spans carrying the policy ID, action, scope,
+// and reason; cancellations with a Reason also set the span status to Error.
+// Direct calls to the returned Enforcer's
+// methods are traced too, without a policy ID for Adjust and Cancel.
+//
+// The returned Enforcer also implements policy.EnforcerCtx: with
+// policy.EnforceCtx each span is a child of the span in ctx, and inner
+// receives the span's context when it is a policy.EnforcerCtx itself.
+func TracingEnforcerWithOptions(inner policy.Enforcer, tracer trace.Tracer, opts TracingOptions) policy.Enforcer {
+	return tracingEnforcer{inner: inner, tracer: tracer, opts: opts}
+}
+
+// tracingEnforcer traces direct calls and binds routed Decisions.
+type tracingEnforcer struct {
+	inner  policy.Enforcer
+	tracer trace.Tracer
+	opts   TracingOptions
+}
+
+// Route implements policy.EnforcerRouter so spans can describe the full
+// Decision.
+func (e tracingEnforcer) Route(d policy.Decision) policy.Enforcer {
+	inner := e.inner
+	if r, ok := inner.(policy.EnforcerRouter); ok {
+		inner = r.Route(d)
+	}
+	return boundSpan{e: e, inner: inner, d: d}
+}
+
+func (e tracingEnforcer) Adjust(scope policy.Scope, fn func(map[string]any)) {
+	e.adjustSpan(scope).Adjust(scope, fn)
+}
+
+func (e tracingEnforcer) Cancel(scope policy.Scope, reason error) {
+	e.cancelSpan(scope, reason).Cancel(scope, reason)
+}
+
+func (e tracingEnforcer) Warn(policyID string, reason error) {
+	e.warnSpan(policyID, reason).Warn(policyID, reason)
+}
+
+// AdjustCtx implements policy.EnforcerCtx.
+func (e tracingEnforcer) AdjustCtx(ctx context.Context, scope policy.Scope, fn func(map[string]any)) {
+	b := e.adjustSpan(scope)
+	b.ctx = ctx
+	b.Adjust(scope, fn)
+}
+
+// CancelCtx implements policy.EnforcerCtx.
+func (e tracingEnforcer) CancelCtx(ctx context.Context, scope policy.Scope, reason error) {
+	b := e.cancelSpan(scope, reason)
+	b.ctx = ctx
+	b.Cancel(scope, reason)
+}
+
+// WarnCtx implements policy.EnforcerCtx.
+func (e tracingEnforcer) WarnCtx(ctx context.Context, policyID string, reason error) {
+	b := e.warnSpan(policyID, reason)
+	b.ctx = ctx
+	b.Warn(policyID, reason)
+}
+
+func (e tracingEnforcer) adjustSpan(scope policy.Scope) boundSpan {
+	return boundSpan{e: e, inner: e.inner, d: policy.Decision{Scope: scope, Action: policy.ActionAdjust}}
+}
+
+func (e tracingEnforcer) cancelSpan(scope policy.Scope, reason error) boundSpan {
+	action := policy.ActionCancelNode
+	switch scope {
+	case policy.ScopeSubtree:
+		action = policy.ActionCancelSubtree
+	case policy.ScopeRoot:
+		action = policy.ActionCancelRoot
+	}
+	return boundSpan{e: e, inner: e.inner, d: policy.Decision{Scope: scope, Action: action, Reason: reason}}
+}
+
+func (e tracingEnforcer) warnSpan(policyID string, reason error) boundSpan {
+	return boundSpan{e: e, inner: e.inner, d: policy.Decision{PolicyID: policyID, Action: policy.ActionWarn, Reason: reason}}
+}
+
+// Commit implements policy.CommitEnforcer by committing inner, if it
+// supports it, so that policy.CommitEnforce flushes through the tracer.
+func (e tracingEnforcer) Commit() error {
+	if c, ok := e.inner.(policy.CommitEnforcer); ok {
+		return c.Commit()
+	}
+	return nil
+}
+
+// boundSpan applies one Decision to inner inside a span describing it. ctx,
+// when set, is the caller's context: the span becomes its child and inner
+// receives the span's context through policy.EnforcerCtx.
+type boundSpan struct {
+	e     tracingEnforcer
+	inner policy.Enforcer
+	d     policy.Decision
+	ctx   context.Context
+}
+
+// span starts the span for b.d and returns it with its context. The caller
+// must end it.
+func (b boundSpan) span() (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{
+		AttrPolicyID.String(b.d.PolicyID),
+		AttrAction.String(b.d.Action.String()),
+		AttrScope.String(b.d.Scope.String()),
+	}
+	if b.d.Reason != nil {
+		attrs = append(attrs, AttrReason.String(b.d.Reason.Error()))
+	}
+	parent := b.ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, span := b.e.tracer.Start(parent, b.e.opts.enforceSpanName(b.d.PolicyID, b.d.Action),
+		trace.WithAttributes(attrs...))
+	switch b.d.Action {
+	case policy.ActionCancelNode, policy.ActionCancelSubtree, policy.ActionCancelRoot:
+		if b.d.Reason != nil {
+			span.SetStatus(codes.Error, b.d.Reason.Error())
+		}
+	}
+	return ctx, span
+}
+
+// innerCtx returns inner as a policy.EnforcerCtx when b carries the caller's
+// context and inner supports it.
+func (b boundSpan) innerCtx() (policy.EnforcerCtx, bool) {
+	if b.ctx == nil {
+		return nil, false
+	}
+	ic, ok := b.inner.(policy.EnforcerCtx)
+	return ic, ok
+}
+
+func (b boundSpan) Adjust(scope policy.Scope, fn func(map[string]any)) {
+	ctx, span := b.span()
+	defer span.End()
+	if ic, ok := b.innerCtx(); ok {
+		ic.AdjustCtx(ctx, scope, fn)
+		return
+	}
+	b.inner.Adjust(scope, fn)
+}
+
+func (b boundSpan) Cancel(scope policy.Scope, reason error) {
+	ctx, span := b.span()
+	defer span.End()
+	if ic, ok := b.innerCtx(); ok {
+		ic.CancelCtx(ctx, scope, reason)
+		return
+	}
+	b.inner.Cancel(scope, reason)
+}
+
+func (b boundSpan) Warn(policyID string, reason error) {
+	ctx, span := b.span()
+	defer span.End()
+	if ic, ok := b.innerCtx(); ok {
+		ic.WarnCtx(ctx, policyID, reason)
+		return
+	}
+	b.inner.Warn(policyID, reason)
+}
+
+// Audit forwards to inner when it is a policy.AuditEnforcer.
+func (b boundSpan) Audit(policyID string, scope policy.Scope, reason error) {
+	a, ok := b.inner.(policy.AuditEnforcer)
+	if !ok {
+		return
+	}
+	_, span := b.span()
+	defer span.End()
+	a.Audit(policyID, scope, reason)
+}
+
+// tracingPolicy wraps a Policy's Check in a span. ID, Priority, and Match are
+// delegated unchanged; the registry sees the wrapped policy's optional
+// interfaces through UnwrapPolicy.
+type tracingPolicy struct {
+	policy.Policy
+	tracer trace.Tracer
+	opts   TracingOptions
+}
+
+// TracingPolicy wraps p so that each Check runs inside a span named by
+// opts.EvaluateSpanName, carrying the policy and node IDs.
+//
+// Under context-aware evaluation (EvaluateContext, EvaluateWithBudget) the
+// span is a child of the span in the evaluation context, and p receives the
+// span's context when it is a policy.ContextPolicy; plain Evaluate starts a
+// root span. p's optional interfaces (PrecisePriority, Explainable, ...)
+// stay in effect, so wrapping does not change the evaluation order.
+func TracingPolicy(p policy.Policy, tracer trace.Tracer, opts TracingOptions) policy.Policy {
+	return tracingPolicy{Policy: p, tracer: tracer, opts: opts}
+}
+
+func (p tracingPolicy) Check(n policy.Node) []policy.Decision {
+	_, span := p.span(context.Background(), n)
+	defer span.End()
+	return p.Policy.Check(n)
+}
+
+// CheckContext implements policy.ContextPolicy.
+func (p tracingPolicy) CheckContext(ctx context.Context, n policy.Node) []policy.Decision {
+	ctx, span := p.span(ctx, n)
+	defer span.End()
+	return policy.CheckPolicyContext(ctx, p.Policy, n)
+}
+
+// UnwrapPolicy implements policy.WrappedPolicy.
+func (p tracingPolicy) UnwrapPolicy() policy.Policy { return p.Policy }
+
+// span starts the evaluation span for n as a child of ctx.
+func (p tracingPolicy) span(ctx context.Context, n policy.Node) (context.Context, trace.Span) {
+	return p.tracer.Start(ctx, p.opts.evaluateSpanName(p.ID()),
+		trace.WithAttributes(AttrPolicyID.String(p.ID()), AttrNodeID.String(n.ID())))
+}
@@ -0,0 +1,179 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policyotel_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/embedded"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+	policyotel "github.com/ArieDeha/ccxpolicy/otel"
+)
+
+// nameTracer records the names of the spans it starts.
+type nameTracer struct {
+	embedded.Tracer
+	names []string
+}
+
+func (t *nameTracer) Start(ctx context.Context, name string, _ ...trace.SpanStartOption) (context.Context, trace.Span) {
+	t.names = append(t.names, name)
+	return noop.NewTracerProvider().Tracer("").Start(ctx, name)
+}
+
+type warnEnforcer struct{ warns []string }
+
+func (e *warnEnforcer) Adjust(policy.Scope, func(map[string]any)) {}
+func (e *warnEnforcer) Cancel(policy.Scope, error)                {}
+func (e *warnEnforcer) Warn(id string, _ error)                   { e.warns = append(e.warns, id) }
+
+func TestTracingEnforcerWithOptions(t *testing.T) {
+	tracer := &nameTracer{}
+	inner := &warnEnforcer{}
+	e := policyotel.TracingEnforcerWithOptions(inner, tracer, policyotel.TracingOptions{
+		EnforceSpanName: func(id string, a policy.Action) string { return "enforce/" + a.String() + "/" + id },
+	})
+
+	policy.Enforce(e, []policy.Decision{
+		{PolicyID: "quota", Action: policy.ActionWarn},
+		{PolicyID: "guard", Action: policy.ActionCancelNode, Reason: policy.Reason("no")},
+	})
+
+	want := []string{"enforce/Warn/quota", "enforce/CancelNode/guard"}
+	if !reflect.DeepEqual(tracer.names, want) {
+		t.Fatalf("spans = %v, want %v", tracer.names, want)
+	}
+	if !reflect.DeepEqual(inner.warns, []string{"quota"}) {
+		t.Fatalf("inner warns = %v", inner.warns)
+	}
+}
+
+type node struct{ id string }
+
+func (n node) ID() string             { return n.id }
+func (n node) Name() string           { return n.id }
+func (n node) Params() map[string]any { return nil }
+func (n node) Parent() policy.Node    { return nil }
+func (n node) Root() policy.Node      { return n }
+
+func TestTracingPolicyDefaultSpanName(t *testing.T) {
+	tracer := &nameTracer{}
+	p, err := policy.PolicyFunc("cap", 1, func(policy.Node) bool { return true }, func(policy.Node) []policy.Decision { return nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := policy.New()
+	r.Register(policyotel.TracingPolicy(p, tracer, policyotel.TracingOptions{}))
+	r.Evaluate(node{id: "n"})
+
+	if want := []string{"ccxpolicy.evaluate cap"}; !reflect.DeepEqual(tracer.names, want) {
+		t.Fatalf("spans = %v, want %v", tracer.names, want)
+	}
+}
+
+// commitEnforcer counts Commit calls.
+type commitEnforcer struct {
+	warnEnforcer
+	commits int
+}
+
+func (e *commitEnforcer) Commit() error { e.commits++; return nil }
+
+func TestTracingEnforcerForwardsCommit(t *testing.T) {
+	inner := &commitEnforcer{}
+	e := policyotel.TracingEnforcerWithOptions(inner, &nameTracer{}, policyotel.TracingOptions{})
+	if err := policy.CommitEnforce(e, []policy.Decision{{PolicyID: "a", Action: policy.ActionWarn}}); err != nil {
+		t.Fatal(err)
+	}
+	if inner.commits != 1 || !reflect.DeepEqual(inner.warns, []string{"a"}) {
+		t.Fatalf("commits = %d, warns = %v", inner.commits, inner.warns)
+	}
+}
+
+// parentTracer records the trace ID of the parent context of every span.
+type parentTracer struct {
+	embedded.Tracer
+	parents []trace.TraceID
+}
+
+func (t *parentTracer) Start(ctx context.Context, name string, _ ...trace.SpanStartOption) (context.Context, trace.Span) {
+	t.parents = append(t.parents, trace.SpanContextFromContext(ctx).TraceID())
+	return noop.NewTracerProvider().Tracer("").Start(ctx, name)
+}
+
+// callerContext returns a context carrying a valid remote span.
+func callerContext() (context.Context, trace.TraceID) {
+	id := trace.TraceID{1, 2, 3}
+	sc := trace.NewSpanContext(trace.SpanContextConfig{TraceID: id, SpanID: trace.SpanID{4}, Remote: true})
+	return trace.ContextWithSpanContext(context.Background(), sc), id
+}
+
+// precisePolicy is a plain policy with a fractional priority.
+type precisePolicy struct {
+	policy.Policy
+	precise float64
+}
+
+func (p precisePolicy) PrecisePriority() float64 { return p.precise }
+
+func TestTracingJoinsCallerTrace(t *testing.T) {
+	ctx, id := callerContext()
+	tracer := &parentTracer{}
+
+	p, err := policy.PolicyFunc("cap", 1, func(policy.Node) bool { return true }, func(policy.Node) []policy.Decision {
+		return []policy.Decision{{PolicyID: "cap", Action: policy.ActionWarn}}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := policy.New()
+	r.Register(policyotel.TracingPolicy(p, tracer, policyotel.TracingOptions{}))
+	ds, err := r.EvaluateContext(ctx, node{id: "n"})
+	if err != nil || len(ds) != 1 {
+		t.Fatalf("EvaluateContext: %+v, %v", ds, err)
+	}
+
+	e := policyotel.TracingEnforcer(&warnEnforcer{}, tracer).(policy.EnforcerCtx)
+	if err := policy.EnforceCtx(ctx, e, ds); err != nil {
+		t.Fatal(err)
+	}
+	if want := []trace.TraceID{id, id}; !reflect.DeepEqual(tracer.parents, want) {
+		t.Fatalf("span parents = %v, want the caller's trace %v", tracer.parents, id)
+	}
+}
+
+func TestTracingPolicyKeepsPrecisePriority(t *testing.T) {
+	mk := func(id string, precise float64) policy.Policy {
+		p, err := policy.PolicyFunc(id, 1, func(policy.Node) bool { return true }, func(policy.Node) []policy.Decision {
+			return []policy.Decision{{PolicyID: id, Action: policy.ActionWarn}}
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return precisePolicy{Policy: p, precise: precise}
+	}
+	r := policy.New()
+	r.Register(policyotel.TracingPolicy(mk("a", 1.9), &nameTracer{}, policyotel.TracingOptions{}))
+	r.Register(mk("b", 1.5))
+	ds := r.Evaluate(node{id: "n"})
+	if len(ds) != 2 || ds[0].PolicyID != "b" {
+		t.Fatalf("order = %+v, want b first", ds)
+	}
+}
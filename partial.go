@@ -0,0 +1,84 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+// PartialEvaluate is the panic-safe variant of Evaluate. A policy whose
+// Match or Check panics contributes no Decisions; the panic is recovered,
+// returned as a *PanicError naming the policy, and evaluation continues with
+// the next policy. The Decisions of every healthy policy are returned as
+// Evaluate would return them (including Stop handling), so they can still be
+// enforced. errs is nil when no policy panicked.
+func (r *Registry) PartialEvaluate(n Node) (ds []Decision, errs []error) {
+	pols := r.snapshot()
+	hooks := r.currentHooks()
+	for i, p := range pols {
+		out, err := r.safeCheck(p, n)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, d := range out {
+			ds = append(ds, d)
+			if d.Stop {
+				if hooks.OnStop != nil {
+					hooks.OnStop(d, pols[i+1:])
+				}
+				return ds, errs
+			}
+		}
+	}
+	return ds, errs
+}
+
+// PartialEvaluate runs Registry.PartialEvaluate against the global registry.
+func PartialEvaluate(n Node) ([]Decision, []error) { return defaultRegistry.PartialEvaluate(n) }
+
+// safeCheck runs p's Match and, if it matches, Check against n as evaluation
+// does, converting a panic in either into a *PanicError.
+func (r *Registry) safeCheck(p Policy, n Node) ([]Decision, error) {
+	_, ds, err := r.inspectCheck(p, n)
+	return ds, err
+}
+
+// inspectCheck is safeCheck that also reports whether Match returned true. A
+// panic in Match leaves matched false; a panic in Check leaves it true.
+func (r *Registry) inspectCheck(p Policy, n Node) (matched bool, ds []Decision, err error) {
+	if matched, err = safeMatch(p, n); err != nil || !matched {
+		return matched, nil, err
+	}
+	ds, err = r.guardedCheck(p, func() []Decision { return checkPolicy(p, n) })
+	return true, ds, err
+}
+
+// safeMatch reports matchPolicy(p, n), converting a panic into a *PanicError.
+func safeMatch(p Policy, n Node) (matched bool, err error) {
+	defer recoverPolicyPanic(p, &err)
+	return matchPolicy(p, n), nil
+}
+
+// guardedCheck post-processes the Decisions returned by check, which runs
+// p's Check, with finishCheck, converting a panic into a *PanicError.
+func (r *Registry) guardedCheck(p Policy, check func() []Decision) (ds []Decision, err error) {
+	defer recoverPolicyPanic(p, &err)
+	return r.finishCheck(p, check()), nil
+}
+
+// recoverPolicyPanic must be deferred directly; it stores a panic raised by
+// p's code in *err as a *PanicError.
+func recoverPolicyPanic(p Policy, err *error) {
+	if v := recover(); v != nil {
+		*err = &PanicError{PolicyID: p.ID(), Value: v}
+	}
+}
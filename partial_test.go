@@ -0,0 +1,61 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+func TestPartialEvaluate(t *testing.T) {
+	r := policy.New()
+	r.Register(warnPolicy{id: "a", prio: 1})
+	r.Register(&policy.FuncPolicy{
+		PolicyID: "bad-match",
+		Prio:     2,
+		MatchFn:  func(policy.Node) bool { panic("match") },
+		CheckFn:  func(policy.Node) []policy.Decision { return nil },
+	})
+	r.Register(&policy.FuncPolicy{
+		PolicyID: "bad-check",
+		Prio:     3,
+		MatchFn:  func(policy.Node) bool { return true },
+		CheckFn:  func(policy.Node) []policy.Decision { panic("check") },
+	})
+	r.Register(warnPolicy{id: "b", prio: 4})
+
+	ds, errs := r.PartialEvaluate(&testNode{id: "n"})
+	if got := decisionIDs(ds); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Fatalf("decisions = %v", got)
+	}
+	if len(errs) != 2 {
+		t.Fatalf("errs = %v", errs)
+	}
+	for i, want := range []string{"bad-match", "bad-check"} {
+		var pe *policy.PanicError
+		if !errors.As(errs[i], &pe) || pe.PolicyID != want {
+			t.Fatalf("errs[%d] = %v, want panic from %q", i, errs[i], want)
+		}
+	}
+
+	healthy := policy.New()
+	healthy.Register(warnPolicy{id: "a"})
+	if ds, errs := healthy.PartialEvaluate(&testNode{id: "n"}); len(ds) != 1 || errs != nil {
+		t.Fatalf("healthy registry: %v %v", ds, errs)
+	}
+}
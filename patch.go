@@ -0,0 +1,174 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+)
+
+// ParamOpKind is the operation of a ParamOp.
+type ParamOpKind int
+
+const (
+	// OpSet stores Value under Key.
+	OpSet ParamOpKind = iota
+	// OpDelete removes Key; Value is ignored.
+	OpDelete
+	// OpMerge copies the entries of Value (a map[string]any) into a copy of
+	// the map stored under Key, or into a new map if Key is absent.
+	OpMerge
+	// OpIncrement adds the number Value to the number stored under Key,
+	// keeping the stored type unless a fractional Value is added to an
+	// integer (the result is then a float64); an absent Key is set to Value.
+	OpIncrement
+)
+
+var paramOpNames = [...]string{
+	OpSet:       "set",
+	OpDelete:    "delete",
+	OpMerge:     "merge",
+	OpIncrement: "increment",
+}
+
+func (k ParamOpKind) String() string {
+	if k >= 0 && int(k) < len(paramOpNames) {
+		return paramOpNames[k]
+	}
+	return fmt.Sprintf("ParamOpKind(%d)", int(k))
+}
+
+// ParseParamOpKind returns the ParamOpKind named s ("set", "delete",
+// "merge", or "increment").
+func ParseParamOpKind(s string) (ParamOpKind, error) {
+	for i, n := range paramOpNames {
+		if n == s {
+			return ParamOpKind(i), nil
+		}
+	}
+	return 0, fmt.Errorf("%w: unknown op %q", ErrInvalidParamOp, s)
+}
+
+// ErrInvalidParamOp is wrapped by the errors ApplyPatch reports for ops that
+// cannot be applied.
+var ErrInvalidParamOp = errors.New("ccxpolicy: invalid param op")
+
+// ParamOp is one declarative parameter change. Unlike an Adjust closure, a
+// list of ParamOps (Decision.Patch) can be serialised, logged, and sent to
+// another process.
+type ParamOp struct {
+	Op    ParamOpKind
+	Key   string
+	Value any
+}
+
+// ApplyPatch applies ops to params in order. An op that cannot be applied (a
+// merge of a non-map, an increment of a non-number, an unknown kind) is
+// skipped and reported; the returned error joins all such problems, each
+// wrapping ErrInvalidParamOp, and is nil if every op applied.
+func ApplyPatch(params map[string]any, ops []ParamOp) error {
+	var errs []error
+	for _, op := range ops {
+		if err := applyParamOp(params, op); err != nil {
+			errs = append(errs, fmt.Errorf("%w: %s %q: %v", ErrInvalidParamOp, op.Op, op.Key, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func applyParamOp(params map[string]any, op ParamOp) error {
+	switch op.Op {
+	case OpSet:
+		params[op.Key] = op.Value
+	case OpDelete:
+		delete(params, op.Key)
+	case OpMerge:
+		add, ok := op.Value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("value is %T, not map[string]any", op.Value)
+		}
+		merged := map[string]any{}
+		if cur, ok := params[op.Key]; ok {
+			m, ok := cur.(map[string]any)
+			if !ok {
+				return fmt.Errorf("current value is %T, not map[string]any", cur)
+			}
+			merged = maps.Clone(m)
+		}
+		maps.Copy(merged, add)
+		params[op.Key] = merged
+	case OpIncrement:
+		cur, ok := params[op.Key]
+		if !ok {
+			if _, ok := toFloat64(op.Value); !ok {
+				return fmt.Errorf("value is %T, not a number", op.Value)
+			}
+			params[op.Key] = op.Value
+			return nil
+		}
+		sum, err := addNumbers(cur, op.Value)
+		if err != nil {
+			return err
+		}
+		params[op.Key] = sum
+	default:
+		return errors.New("unknown op")
+	}
+	return nil
+}
+
+// addNumbers returns cur+delta converted to cur's numeric type, or as a
+// float64 when cur is an integer and delta has a fractional part.
+func addNumbers(cur, delta any) (any, error) {
+	c, ok := toFloat64(cur)
+	if !ok {
+		return nil, fmt.Errorf("current value is %T, not a number", cur)
+	}
+	d, ok := toFloat64(delta)
+	if !ok {
+		return nil, fmt.Errorf("value is %T, not a number", delta)
+	}
+	switch cur.(type) {
+	case float32, float64:
+	default:
+		if d != math.Trunc(d) {
+			return c + d, nil
+		}
+	}
+	return convertLike(cur, c+d), nil
+}
+
+// EffectiveAdjust returns the function that applying d's adjustment runs:
+// d.Adjust followed by ApplyPatch(d.Patch), either alone when the other is
+// unset, or nil when d has neither. Errors from the patch are dropped, as
+// Enforcer.Adjust cannot report them; call ApplyPatch directly when they
+// matter.
+func EffectiveAdjust(d Decision) func(map[string]any) {
+	switch {
+	case len(d.Patch) == 0:
+		return d.Adjust
+	case d.Adjust == nil:
+		patch := d.Patch
+		return func(params map[string]any) { _ = ApplyPatch(params, patch) }
+	default:
+		fn, patch := d.Adjust, d.Patch
+		return func(params map[string]any) {
+			fn(params)
+			_ = ApplyPatch(params, patch)
+		}
+	}
+}
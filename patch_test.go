@@ -0,0 +1,114 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+func TestApplyPatch(t *testing.T) {
+	params := map[string]any{
+		"quality": 1080,
+		"ratio":   float32(1.5),
+		"gone":    true,
+		"labels":  map[string]any{"team": "media"},
+	}
+	labels := params["labels"].(map[string]any)
+	err := policy.ApplyPatch(params, []policy.ParamOp{
+		{Op: policy.OpSet, Key: "codec", Value: "av1"},
+		{Op: policy.OpDelete, Key: "gone"},
+		{Op: policy.OpMerge, Key: "labels", Value: map[string]any{"tier": "gold"}},
+		{Op: policy.OpMerge, Key: "fresh", Value: map[string]any{"a": 1}},
+		{Op: policy.OpIncrement, Key: "quality", Value: -360},
+		{Op: policy.OpIncrement, Key: "ratio", Value: 1},
+		{Op: policy.OpIncrement, Key: "retries", Value: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"quality": 720,
+		"ratio":   float32(2.5),
+		"codec":   "av1",
+		"labels":  map[string]any{"team": "media", "tier": "gold"},
+		"fresh":   map[string]any{"a": 1},
+		"retries": 1,
+	}
+	if !reflect.DeepEqual(params, want) {
+		t.Fatalf("params = %v, want %v", params, want)
+	}
+	if len(labels) != 1 {
+		t.Fatalf("merge modified the original nested map: %v", labels)
+	}
+
+	params = map[string]any{"n": 1, "s": "x"}
+	err = policy.ApplyPatch(params, []policy.ParamOp{
+		{Op: policy.OpIncrement, Key: "s", Value: 1},
+		{Op: policy.OpMerge, Key: "n", Value: map[string]any{}},
+		{Op: policy.OpIncrement, Key: "n", Value: 0.5},
+		{Op: policy.ParamOpKind(99), Key: "n"},
+	})
+	if !errors.Is(err, policy.ErrInvalidParamOp) || strings.Count(err.Error(), "\n") != 2 {
+		t.Fatalf("expected three joined ErrInvalidParamOp errors, got %v", err)
+	}
+	if params["n"] != 1.5 || params["s"] != "x" {
+		t.Fatalf("valid ops should still apply: %v", params)
+	}
+}
+
+func TestEnforceAppliesPatch(t *testing.T) {
+	e := &paramEnforcer{params: map[string]any{"q": 10}}
+	policy.Enforce(e, []policy.Decision{
+		{Action: policy.ActionAdjust, Patch: []policy.ParamOp{{Op: policy.OpIncrement, Key: "q", Value: 5}}},
+		{
+			Action: policy.ActionAdjust,
+			Adjust: func(m map[string]any) { m["q"] = m["q"].(int) * 2 },
+			Patch:  []policy.ParamOp{{Op: policy.OpSet, Key: "done", Value: true}},
+		},
+	})
+	if want := map[string]any{"q": 30, "done": true}; !reflect.DeepEqual(e.params, want) {
+		t.Fatalf("params = %v, want %v", e.params, want)
+	}
+	if policy.EffectiveAdjust(policy.Decision{Action: policy.ActionAdjust}) != nil {
+		t.Fatal("EffectiveAdjust should be nil without Adjust and Patch")
+	}
+}
+
+func TestDecisionBatchWriterEncodesPatch(t *testing.T) {
+	var buf bytes.Buffer
+	w := policy.NewDecisionBatchWriter(&buf)
+	if err := w.Write([]policy.Decision{{
+		PolicyID: "p",
+		Action:   policy.ActionAdjust,
+		Patch:    []policy.ParamOp{{Op: policy.OpSet, Key: "q", Value: 720}, {Op: policy.OpDelete, Key: "x"}},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if want := `"patch":[{"op":"set","key":"q","value":720},{"op":"delete","key":"x"}]`; !strings.Contains(buf.String(), want) {
+		t.Fatalf("output %s lacks %s", buf.String(), want)
+	}
+	if k, err := policy.ParseParamOpKind("merge"); err != nil || k != policy.OpMerge {
+		t.Fatalf("ParseParamOpKind(merge) = %v, %v", k, err)
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+import (
+	"fmt"
+	"time"
+)
+
+// DecisionTransformer rewrites a Decision slice between Evaluate and Enforce.
+// It may modify and return its input.
+type DecisionTransformer func([]Decision) []Decision
+
+// Pipeline runs DecisionTransformers in the order they were added. The zero
+// value is an empty pipeline that returns its input unchanged. A Pipeline
+// must not be modified while Process runs.
+type Pipeline struct {
+	transformers []DecisionTransformer
+}
+
+// Add appends t to the pipeline and returns p, so calls can be chained.
+func (p *Pipeline) Add(t DecisionTransformer) *Pipeline {
+	p.transformers = append(p.transformers, t)
+	return p
+}
+
+// Process passes ds through every transformer in turn. A nil pipeline
+// returns ds unchanged.
+func (p *Pipeline) Process(ds []Decision) []Decision {
+	if p == nil {
+		return ds
+	}
+	for _, t := range p.transformers {
+		ds = t(ds)
+	}
+	return ds
+}
+
+// EvaluateWithPipeline evaluates n like Evaluate and returns the result of
+// pl.Process on the decisions.
+func (r *Registry) EvaluateWithPipeline(n Node, pl *Pipeline) []Decision {
+	return pl.Process(r.Evaluate(n))
+}
+
+// EvaluateWithPipeline runs Registry.EvaluateWithPipeline on the global
+// registry.
+func EvaluateWithPipeline(n Node, pl *Pipeline) []Decision {
+	return defaultRegistry.EvaluateWithPipeline(n, pl)
+}
+
+// TransformDropNoop removes ActionNoop decisions, which have no effect.
+func TransformDropNoop() DecisionTransformer {
+	return func(ds []Decision) []Decision {
+		out := ds[:0]
+		for _, d := range ds {
+			if d.Action != ActionNoop {
+				out = append(out, d)
+			}
+		}
+		return out
+	}
+}
+
+// TransformDedup drops repeated decisions, keeping the first occurrence. Two
+// decisions are duplicates when their PolicyID, Scope, Action, Reason text,
+// Stop, and EnforcerTag are equal. Adjust decisions are always kept, since
+// their functions cannot be compared.
+func TransformDedup() DecisionTransformer {
+	type key struct {
+		policyID, reason, tag string
+		scope                 Scope
+		action                Action
+		stop                  bool
+	}
+	return func(ds []Decision) []Decision {
+		seen := map[key]struct{}{}
+		out := ds[:0]
+		for _, d := range ds {
+			if d.Action != ActionAdjust {
+				k := key{policyID: d.PolicyID, tag: d.EnforcerTag, scope: d.Scope, action: d.Action, stop: d.Stop}
+				if d.Reason != nil {
+					k.reason = fmt.Sprint(d.Reason)
+				}
+				if _, dup := seen[k]; dup {
+					continue
+				}
+				seen[k] = struct{}{}
+			}
+			out = append(out, d)
+		}
+		return out
+	}
+}
+
+// TransformEnforceOrdering cuts ds after the first Decision with Stop set,
+// leaving exactly the sequence Enforce would apply. Later transformers and
+// sinks (reports, audit logs) then see what was actually enforced.
+func TransformEnforceOrdering() DecisionTransformer {
+	return func(ds []Decision) []Decision {
+		for i, d := range ds {
+			if d.Stop {
+				return ds[:i+1]
+			}
+		}
+		return ds
+	}
+}
+
+// TransformAddTimestamp sets Timestamp to the current time on every decision
+// that has none. All decisions stamped by one call share the same time.
+func TransformAddTimestamp() DecisionTransformer {
+	return func(ds []Decision) []Decision {
+		now := time.Now()
+		for i := range ds {
+			if ds[i].Timestamp.IsZero() {
+				ds[i].Timestamp = now
+			}
+		}
+		return ds
+	}
+}
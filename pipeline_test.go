@@ -0,0 +1,73 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"reflect"
+	"testing"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+func TestPipeline(t *testing.T) {
+	ds := []policy.Decision{
+		{PolicyID: "noop", Action: policy.ActionNoop},
+		{PolicyID: "w", Action: policy.ActionWarn, Reason: policy.Reason("x")},
+		{PolicyID: "w", Action: policy.ActionWarn, Reason: policy.Reason("x")},
+		{PolicyID: "adj", Action: policy.ActionAdjust},
+		{PolicyID: "adj", Action: policy.ActionAdjust},
+		{PolicyID: "stop", Action: policy.ActionCancelNode, Stop: true},
+		{PolicyID: "after", Action: policy.ActionWarn},
+	}
+
+	pl := &policy.Pipeline{}
+	pl.Add(policy.TransformDropNoop()).
+		Add(policy.TransformDedup()).
+		Add(policy.TransformEnforceOrdering()).
+		Add(policy.TransformAddTimestamp())
+	out := pl.Process(ds)
+
+	if got, want := decisionIDs(out), []string{"w", "adj", "adj", "stop"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("processed = %v, want %v", got, want)
+	}
+	for _, d := range out {
+		if d.Timestamp.IsZero() || !d.Timestamp.Equal(out[0].Timestamp) {
+			t.Fatalf("decision %q not stamped consistently: %v", d.PolicyID, d.Timestamp)
+		}
+	}
+}
+
+func TestEvaluateWithPipeline(t *testing.T) {
+	r := policy.New()
+	r.Register(warnPolicy{id: "a", prio: 1})
+	r.Register(warnPolicy{id: "b", prio: 2})
+
+	dropA := func(ds []policy.Decision) []policy.Decision {
+		out := ds[:0]
+		for _, d := range ds {
+			if d.PolicyID != "a" {
+				out = append(out, d)
+			}
+		}
+		return out
+	}
+	got := decisionIDs(r.EvaluateWithPipeline(&testNode{id: "n"}, (&policy.Pipeline{}).Add(dropA)))
+	if !reflect.DeepEqual(got, []string{"b"}) {
+		t.Fatalf("got %v", got)
+	}
+	if got := r.EvaluateWithPipeline(&testNode{id: "n"}, nil); len(got) != 2 {
+		t.Fatalf("nil pipeline changed the result: %+v", got)
+	}
+}
@@ -23,7 +23,12 @@
 // (e.g., registry.go). This file contains the core interfaces and types.
 package ccxpolicy
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
 
 // Scope indicates where a Decision should be applied within the host runtime's
 // execution tree. The actual meaning of Node/Subtree/Root is defined by the
@@ -39,6 +44,47 @@ const (
 	ScopeRoot
 )
 
+var scopeNames = [...]string{
+	ScopeNode:    "Node",
+	ScopeSubtree: "Subtree",
+	ScopeRoot:    "Root",
+}
+
+// String returns the scope name without its prefix (e.g., "Subtree").
+// Unknown values render as "Scope(N)".
+func (s Scope) String() string {
+	if s >= 0 && int(s) < len(scopeNames) {
+		return scopeNames[s]
+	}
+	return fmt.Sprintf("Scope(%d)", int(s))
+}
+
+// ParseScope is the inverse of Scope.String. Matching is case-insensitive and
+// ignores surrounding whitespace; unknown names yield an error wrapping
+// ErrUnknownScope.
+func ParseScope(s string) (Scope, error) {
+	name := strings.TrimSpace(s)
+	for i, n := range scopeNames {
+		if strings.EqualFold(n, name) {
+			return Scope(i), nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %q", ErrUnknownScope, s)
+}
+
+// MarshalText encodes s as its String name, so it reads well in JSON.
+func (s Scope) MarshalText() ([]byte, error) { return []byte(s.String()), nil }
+
+// UnmarshalText decodes a name accepted by ParseScope.
+func (s *Scope) UnmarshalText(b []byte) error {
+	v, err := ParseScope(string(b))
+	if err != nil {
+		return err
+	}
+	*s = v
+	return nil
+}
+
 // Action represents the operation to perform when a policy rule triggers.
 // The host runtime decides how to realize these actions (typically via an
 // Enforcer): e.g., adjust parameters, cancel work, or just warn/log.
@@ -57,6 +103,61 @@ const (
 	ActionCancelSubtree
 	// ActionCancelRoot cancels/aborts the root of the target's tree.
 	ActionCancelRoot
+	// ActionAudit records the decision in an append-only audit trail (see
+	// AuditEnforcer). Unlike ActionWarn it is guaranteed to have no side
+	// effect beyond the record.
+	ActionAudit
+)
+
+var actionNames = [...]string{
+	ActionNoop:          "Noop",
+	ActionWarn:          "Warn",
+	ActionAdjust:        "Adjust",
+	ActionCancelNode:    "CancelNode",
+	ActionCancelSubtree: "CancelSubtree",
+	ActionCancelRoot:    "CancelRoot",
+	ActionAudit:         "Audit",
+}
+
+// String returns the action name without its prefix (e.g., "CancelRoot").
+// Unknown values render as "Action(N)".
+func (a Action) String() string {
+	if a >= 0 && int(a) < len(actionNames) {
+		return actionNames[a]
+	}
+	return fmt.Sprintf("Action(%d)", int(a))
+}
+
+// ParseAction is the inverse of Action.String. Matching is case-insensitive
+// and ignores surrounding whitespace; unknown names yield an error wrapping
+// ErrUnknownAction.
+func ParseAction(s string) (Action, error) {
+	name := strings.TrimSpace(s)
+	for i, n := range actionNames {
+		if strings.EqualFold(n, name) {
+			return Action(i), nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %q", ErrUnknownAction, s)
+}
+
+// MarshalText encodes a as its String name, so it reads well in JSON.
+func (a Action) MarshalText() ([]byte, error) { return []byte(a.String()), nil }
+
+// UnmarshalText decodes a name accepted by ParseAction.
+func (a *Action) UnmarshalText(b []byte) error {
+	v, err := ParseAction(string(b))
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
+
+// Parse errors returned by ParseScope and ParseAction.
+var (
+	ErrUnknownScope  = errors.New("ccxpolicy: unknown scope")
+	ErrUnknownAction = errors.New("ccxpolicy: unknown action")
 )
 
 // Decision is the unit result emitted by a Policy's Check. A policy may return
@@ -66,15 +167,28 @@ const (
 //   - Scope:    where to apply the decision (Node/Subtree/Root).
 //   - Action:   what to do (Warn/Adjust/Cancel*).
 //   - Adjust:   functional update applied to Params when ActionAdjust.
+//   - Patch:    declarative, serialisable alternative to Adjust, applied
+//     after it (see ParamOp and EffectiveAdjust).
 //   - Reason:   operator-friendly message explaining why the decision fired.
 //   - Stop:     if true, short-circuit evaluation of lower-priority policies.
+//   - EnforcerTag: optional routing key naming the subsystem enforcer that
+//     should apply the decision (see TaggedEnforcer).
+//   - Timestamp: when the decision was made; zero unless a policy or a
+//     pipeline stage sets it (see TransformAddTimestamp).
+//   - Metadata: optional annotations for enforcers and audit sinks, e.g. the
+//     node snapshot attached by EnforceWithNodeSnapshot. Treat it as
+//     read-only once the Decision is shared.
 type Decision struct {
-	PolicyID string
-	Scope    Scope
-	Action   Action
-	Adjust   func(params map[string]any) // used only with ActionAdjust
-	Reason   error                       // explanatory message for operators
-	Stop     bool                        // short-circuit further policy evaluation
+	PolicyID    string
+	Scope       Scope
+	Action      Action
+	Adjust      func(params map[string]any) // used only with ActionAdjust
+	Patch       []ParamOp                   // used only with ActionAdjust; after Adjust
+	Reason      error                       // explanatory message for operators
+	Stop        bool                        // short-circuit further policy evaluation
+	EnforcerTag string                      // routes to a named enforcer; "" = default
+	Timestamp   time.Time                   // zero = not recorded
+	Metadata    map[string]any              // optional annotations; nil = none
 }
 
 // Node describes the read-only view of a runtime element that policies inspect.
@@ -99,7 +213,8 @@ type Node interface {
 type Policy interface {
 	// ID returns a unique identifier for diagnostics and auditing.
 	ID() string
-	// Priority controls evaluation order; lower values run earlier.
+	// Priority controls evaluation order; lower values run earlier. Any int
+	// is valid, including negative values (see PriorityEmergency).
 	Priority() int
 	// Match quickly determines whether this policy applies to the node.
 	Match(n Node) bool
@@ -107,6 +222,100 @@ type Policy interface {
 	Check(n Node) []Decision
 }
 
+// Recommended priority tiers. They are conventions only: the registry does not
+// enforce them, and any int (including values between tiers) is fully
+// supported. Leaving gaps lets new policies slot in without renumbering.
+//
+// Negative priorities are reserved by convention for emergency and override
+// policies (kill switches and the like) that must run before every regular
+// policy, PriorityFirst included.
+const (
+	PriorityEmergency = -100 // kill switches and overrides
+	PriorityFirst     = 0    // first regular tier
+	PrioritySecurity  = 100  // access control, isolation, mutual exclusion
+	PriorityQuota     = 200  // rate, size and cost limits
+	PriorityQuality   = 300  // parameter tuning and quality caps
+	PriorityAudit     = 400  // observability-only policies (Warn)
+	PriorityLast      = 999  // runs after everything else
+)
+
+// PrecisePriority is an optional interface for policies that need fractional
+// ordering. When implemented, PrecisePriority() is used instead of Priority()
+// to order the policy, which makes it possible to slot a policy between two
+// existing integer priorities without renumbering (see PriorityBetween).
+// Priority() should still return a sensible integer approximation.
+type PrecisePriority interface {
+	PrecisePriority() float64
+}
+
+// EffectivePriority returns the value used to order p: PrecisePriority() when p
+// implements PrecisePriority, otherwise float64(p.Priority()).
+func EffectivePriority(p Policy) float64 {
+	if pp, ok := asPolicy[PrecisePriority](p); ok {
+		return pp.PrecisePriority()
+	}
+	return float64(p.Priority())
+}
+
+// PriorityBetween returns the midpoint of a and b, a priority that orders
+// strictly between them whenever a != b.
+func PriorityBetween(a, b float64) float64 { return (a + b) / 2 }
+
+// PolicyEqual reports whether a and b have the same ID and effective priority.
+// Behaviour (Match/Check) cannot be compared, so two policies reporting equal
+// identity are treated as duplicates. Two nil policies are equal.
+func PolicyEqual(a, b Policy) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.ID() == b.ID() && EffectivePriority(a) == EffectivePriority(b)
+}
+
+// UniqueByID returns the policies with duplicate IDs removed, keeping the
+// first occurrence of each ID and the input order. The input is not modified.
+func UniqueByID(policies []Policy) []Policy {
+	seen := make(map[string]struct{}, len(policies))
+	out := make([]Policy, 0, len(policies))
+	for _, p := range policies {
+		if _, dup := seen[p.ID()]; dup {
+			continue
+		}
+		seen[p.ID()] = struct{}{}
+		out = append(out, p)
+	}
+	return out
+}
+
+// PolicyAPIVersion is the version of the Policy contract implemented by this
+// package. It is bumped whenever the contract changes incompatibly.
+const PolicyAPIVersion = 1
+
+// ErrPolicyAPIMismatch is returned by CheckPolicyAPIVersion when a policy was
+// written against an incompatible version of the Policy contract.
+var ErrPolicyAPIMismatch = errors.New("ccxpolicy: policy API version mismatch")
+
+// VersionedPolicy is an optional interface for policies that declare which
+// version of the Policy contract they were written against.
+type VersionedPolicy interface {
+	PolicyVersion() int
+}
+
+// CheckPolicyAPIVersion returns an error wrapping ErrPolicyAPIMismatch if p
+// implements VersionedPolicy and reports a version other than
+// PolicyAPIVersion. Policies that do not declare a version are assumed
+// compatible and yield nil.
+func CheckPolicyAPIVersion(p Policy) error {
+	vp, ok := asPolicy[VersionedPolicy](p)
+	if !ok {
+		return nil
+	}
+	if v := vp.PolicyVersion(); v != PolicyAPIVersion {
+		return fmt.Errorf("%w: policy %q targets v%d, package implements v%d",
+			ErrPolicyAPIMismatch, p.ID(), v, PolicyAPIVersion)
+	}
+	return nil
+}
+
 // Reason constructs a simple error value for use as Decision.Reason.
 // It is a convenience helper to avoid importing errors at call sites.
 func Reason(msg string) error { return errors.New(msg) }
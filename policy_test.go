@@ -15,7 +15,9 @@
 package ccxpolicy_test
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
 	"testing"
 
 	policy "github.com/ArieDeha/ccxpolicy"
@@ -28,6 +30,105 @@ func TestReason(t *testing.T) {
 	}
 }
 
+func TestScopeAndActionRoundTrip(t *testing.T) {
+	for _, s := range []policy.Scope{policy.ScopeNode, policy.ScopeSubtree, policy.ScopeRoot} {
+		got, err := policy.ParseScope(s.String())
+		if err != nil || got != s {
+			t.Fatalf("ParseScope(%q) = %v, %v", s.String(), got, err)
+		}
+	}
+	for a := policy.ActionNoop; a <= policy.ActionCancelRoot; a++ {
+		got, err := policy.ParseAction(a.String())
+		if err != nil || got != a {
+			t.Fatalf("ParseAction(%q) = %v, %v", a.String(), got, err)
+		}
+	}
+
+	if s, err := policy.ParseScope(" subtree "); err != nil || s != policy.ScopeSubtree {
+		t.Fatalf("case-insensitive scope: %v, %v", s, err)
+	}
+	if a, err := policy.ParseAction("CANCELROOT"); err != nil || a != policy.ActionCancelRoot {
+		t.Fatalf("case-insensitive action: %v, %v", a, err)
+	}
+	if _, err := policy.ParseScope("leaf"); !errors.Is(err, policy.ErrUnknownScope) {
+		t.Fatalf("unknown scope: got %v", err)
+	}
+	if _, err := policy.ParseAction("explode"); !errors.Is(err, policy.ErrUnknownAction) {
+		t.Fatalf("unknown action: got %v", err)
+	}
+	if got := policy.Scope(9).String(); got != "Scope(9)" {
+		t.Fatalf("unknown scope string: %q", got)
+	}
+}
+
+// precisePolicy orders by a fractional priority.
+type precisePolicy struct {
+	warnPolicy
+	precise float64
+}
+
+func (p precisePolicy) PrecisePriority() float64 { return p.precise }
+
+func TestPrecisePriorityOrdering(t *testing.T) {
+	r := policy.New()
+	r.Register(warnPolicy{id: "ten", prio: 10})
+	r.Register(warnPolicy{id: "eleven", prio: 11})
+	r.Register(precisePolicy{warnPolicy: warnPolicy{id: "between", prio: 10}, precise: policy.PriorityBetween(10, 11)})
+
+	ds := r.Evaluate(&testNode{id: "n"})
+	var ids []string
+	for _, d := range ds {
+		ids = append(ids, d.PolicyID)
+	}
+	if want := []string{"ten", "between", "eleven"}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("order = %v, want %v", ids, want)
+	}
+	if got := policy.PriorityBetween(10, 11); got != 10.5 {
+		t.Fatalf("PriorityBetween = %v", got)
+	}
+}
+
+func TestPolicyEqualAndUniqueByID(t *testing.T) {
+	a := warnPolicy{id: "x", prio: 1, label: "first"}
+	b := warnPolicy{id: "x", prio: 1, label: "second"}
+	c := warnPolicy{id: "x", prio: 2}
+	d := warnPolicy{id: "y", prio: 1}
+
+	if !policy.PolicyEqual(a, b) || policy.PolicyEqual(a, c) || policy.PolicyEqual(a, d) {
+		t.Fatalf("PolicyEqual compares ID and priority only")
+	}
+	if !policy.PolicyEqual(nil, nil) || policy.PolicyEqual(a, nil) {
+		t.Fatalf("PolicyEqual nil handling")
+	}
+
+	got := policy.UniqueByID([]policy.Policy{a, d, b, c})
+	if len(got) != 2 || got[0] != policy.Policy(a) || got[1] != policy.Policy(d) {
+		t.Fatalf("UniqueByID = %v", got)
+	}
+}
+
+// versionedPolicy declares the Policy contract version it targets.
+type versionedPolicy struct {
+	warnPolicy
+	version int
+}
+
+func (p versionedPolicy) PolicyVersion() int { return p.version }
+
+func TestCheckPolicyAPIVersion(t *testing.T) {
+	if err := policy.CheckPolicyAPIVersion(warnPolicy{id: "plain"}); err != nil {
+		t.Fatalf("unversioned policy: %v", err)
+	}
+	ok := versionedPolicy{warnPolicy{id: "ok"}, policy.PolicyAPIVersion}
+	if err := policy.CheckPolicyAPIVersion(ok); err != nil {
+		t.Fatalf("current version: %v", err)
+	}
+	future := versionedPolicy{warnPolicy{id: "future"}, policy.PolicyAPIVersion + 1}
+	if err := policy.CheckPolicyAPIVersion(future); !errors.Is(err, policy.ErrPolicyAPIMismatch) {
+		t.Fatalf("future version: got %v", err)
+	}
+}
+
 // Compile-time interface checks via dummy implementations.
 
 type _dummyNode struct{}
@@ -72,3 +173,15 @@ func ExampleDecision_adjust() {
 	fmt.Println(params["q"])
 	// Output: 1080
 }
+
+func TestPriorityTiersAreOrdered(t *testing.T) {
+	tiers := []int{
+		policy.PriorityEmergency, policy.PriorityFirst, policy.PrioritySecurity, policy.PriorityQuota,
+		policy.PriorityQuality, policy.PriorityAudit, policy.PriorityLast,
+	}
+	for i := 1; i < len(tiers); i++ {
+		if tiers[i-1] >= tiers[i] {
+			t.Fatalf("tier %d (%d) does not precede tier %d (%d)", i-1, tiers[i-1], i, tiers[i])
+		}
+	}
+}
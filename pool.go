@@ -0,0 +1,64 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// RegistryPool spreads evaluations across identical Registry replicas so that
+// very high-throughput hosts do not contend on one registry's lock.
+type RegistryPool struct {
+	mu       sync.Mutex // serialises registration across replicas
+	replicas []*Registry
+	next     atomic.Uint64
+}
+
+// NewRegistryPool returns a pool of size replicas, each created with opts.
+// A size below 1 is treated as 1.
+func NewRegistryPool(size int, opts ...RegistryOption) *RegistryPool {
+	if size < 1 {
+		size = 1
+	}
+	p := &RegistryPool{replicas: make([]*Registry, size)}
+	for i := range p.replicas {
+		p.replicas[i] = New(opts...)
+	}
+	return p
+}
+
+// RegisterPolicy registers pol on every replica. Registrations are
+// serialised by a pool-wide lock, so concurrent calls reach all replicas in
+// the same order; evaluations running meanwhile may briefly see the policy
+// on some replicas only. Register at startup when that matters.
+func (p *RegistryPool) RegisterPolicy(pol Policy) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, r := range p.replicas {
+		r.Register(pol)
+	}
+}
+
+// EvaluateFromPool evaluates n on the next replica in round-robin order.
+// Policies are shared between replicas, so they must be safe for concurrent
+// use, as with a single Registry evaluated concurrently.
+func (p *RegistryPool) EvaluateFromPool(n Node) []Decision {
+	i := p.next.Add(1) - 1
+	return p.replicas[i%uint64(len(p.replicas))].Evaluate(n)
+}
+
+// Size returns the number of replicas.
+func (p *RegistryPool) Size() int { return len(p.replicas) }
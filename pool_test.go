@@ -0,0 +1,42 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"sync"
+	"testing"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+func TestRegistryPool(t *testing.T) {
+	pool := policy.NewRegistryPool(4)
+	if pool.Size() != 4 {
+		t.Fatalf("Size() = %d", pool.Size())
+	}
+	pool.RegisterPolicy(warnPolicy{id: "w"})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if ds := pool.EvaluateFromPool(&testNode{id: "n"}); len(ds) != 1 || ds[0].PolicyID != "w" {
+				t.Errorf("replica returned %+v", ds)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// reprioritized overrides the priority of a registered policy. Priority
+// reports the override rounded to the nearest integer.
+type reprioritized struct {
+	Policy
+	priority float64
+}
+
+func (p reprioritized) Priority() int            { return int(math.Round(p.priority)) }
+func (p reprioritized) PrecisePriority() float64 { return p.priority }
+
+func (p reprioritized) unwrapPolicy() Policy                   { return p.Policy }
+func (p reprioritized) wrapDecisions(ds []Decision) []Decision { return ds }
+func (p reprioritized) wrapNode(n Node) Node                   { return n }
+func (p reprioritized) rewrapPolicy(inner Policy) Policy {
+	p.Policy = inner
+	return p
+}
+
+// withPriority returns p ordered at priority, unwrapping any earlier override.
+func withPriority(p Policy, priority float64) Policy {
+	if rp, ok := p.(reprioritized); ok {
+		p = rp.Policy
+	}
+	if EffectivePriority(p) == priority {
+		return p
+	}
+	return reprioritized{Policy: p, priority: priority}
+}
+
+// SwapPriorities atomically exchanges the effective priorities of the
+// policies registered in r under idA and idB, then re-sorts r. Evaluations
+// see either the old or the new order, never a state with one policy missing.
+// The policies themselves are untouched: r orders them through a wrapper
+// reporting the swapped priority, so swapping back restores the originals.
+// The wrapper does not hide the policy's optional interfaces (Resettable,
+// Explainable, ContextPolicy and so on), and a swapped dry-run policy can
+// still be promoted.
+//
+// It returns an error wrapping ErrPolicyNotFound if either ID is not
+// registered in r, in which case nothing changes. On success the cache is
+// cleared and a PolicyReplaced event is emitted for each policy.
+func (r *Registry) SwapPriorities(idA, idB string) error {
+	r.mu.Lock()
+	i, j := indexPolicy(r.policies, idA), indexPolicy(r.policies, idB)
+	switch {
+	case i < 0:
+		r.mu.Unlock()
+		return fmt.Errorf("%w: %q", ErrPolicyNotFound, idA)
+	case j < 0:
+		r.mu.Unlock()
+		return fmt.Errorf("%w: %q", ErrPolicyNotFound, idB)
+	}
+	a, b := r.policies[i], r.policies[j]
+	pa, pb := EffectivePriority(a), EffectivePriority(b)
+	a, b = withPriority(a, pb), withPriority(b, pa)
+	r.policies[i], r.policies[j] = a, b
+	sort.SliceStable(r.policies, func(i, j int) bool {
+		return policyLess(r.policies[i], r.policies[j])
+	})
+	r.mu.Unlock()
+
+	r.changed(
+		RegistryEvent{Type: PolicyReplaced, PolicyID: idA, Policy: a},
+		RegistryEvent{Type: PolicyReplaced, PolicyID: idB, Policy: b},
+	)
+	return nil
+}
+
+// SwapPriorities exchanges two policies' priorities in the global registry.
+func SwapPriorities(idA, idB string) error { return defaultRegistry.SwapPriorities(idA, idB) }
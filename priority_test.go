@@ -0,0 +1,75 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+func TestSwapPriorities(t *testing.T) {
+	r := policy.New()
+	r.Register(warnPolicy{id: "incumbent", prio: 10})
+	r.Register(warnPolicy{id: "middle", prio: 20})
+	r.Register(warnPolicy{id: "newcomer", prio: 30})
+	n := &testNode{id: "n"}
+
+	if err := r.SwapPriorities("incumbent", "newcomer"); err != nil {
+		t.Fatalf("SwapPriorities: %v", err)
+	}
+	if got, want := decisionIDs(r.Evaluate(n)), []string{"newcomer", "middle", "incumbent"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("order after swap = %v, want %v", got, want)
+	}
+
+	if err := r.SwapPriorities("newcomer", "incumbent"); err != nil {
+		t.Fatalf("swap back: %v", err)
+	}
+	if got, want := decisionIDs(r.Evaluate(n)), []string{"incumbent", "middle", "newcomer"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("order after swapping back = %v, want %v", got, want)
+	}
+	for _, p := range r.Policies() {
+		if _, ok := p.(warnPolicy); !ok {
+			t.Fatalf("swapping back left %q wrapped as %T", p.ID(), p)
+		}
+	}
+
+	if err := r.SwapPriorities("incumbent", "ghost"); !errors.Is(err, policy.ErrPolicyNotFound) {
+		t.Fatalf("unknown ID: got %v", err)
+	}
+}
+
+func TestSwapPrioritiesKeepsDryRunPromotable(t *testing.T) {
+	r := policy.New()
+	if err := r.RegisterDryRun(warnPolicy{id: "staged", prio: 10}); err != nil {
+		t.Fatal(err)
+	}
+	r.Register(precisePolicy{warnPolicy: warnPolicy{id: "precise", prio: 2}, precise: 2.5})
+	if err := r.SwapPriorities("staged", "precise"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.PromotePolicy("staged"); err != nil {
+		t.Fatalf("PromotePolicy after swap: %v", err)
+	}
+	p, _ := r.GetPolicy("staged")
+	if policy.EffectivePriority(p) != 2.5 || p.Priority() != 3 {
+		t.Fatalf("promotion lost the swapped priority: %v / %d", policy.EffectivePriority(p), p.Priority())
+	}
+	if err := r.PromotePolicy("staged"); !errors.Is(err, policy.ErrNotDryRun) {
+		t.Fatalf("second promotion: %v", err)
+	}
+}
@@ -0,0 +1,522 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: ccx/v1/decision.proto
+
+package ccxv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Scope mirrors ccxpolicy.Scope. Values match the Go constants, so the zero
+// value is SCOPE_NODE as in Go.
+type Scope int32
+
+const (
+	Scope_SCOPE_NODE    Scope = 0
+	Scope_SCOPE_SUBTREE Scope = 1
+	Scope_SCOPE_ROOT    Scope = 2
+)
+
+// Enum value maps for Scope.
+var (
+	Scope_name = map[int32]string{
+		0: "SCOPE_NODE",
+		1: "SCOPE_SUBTREE",
+		2: "SCOPE_ROOT",
+	}
+	Scope_value = map[string]int32{
+		"SCOPE_NODE":    0,
+		"SCOPE_SUBTREE": 1,
+		"SCOPE_ROOT":    2,
+	}
+)
+
+func (x Scope) Enum() *Scope {
+	p := new(Scope)
+	*p = x
+	return p
+}
+
+func (x Scope) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Scope) Descriptor() protoreflect.EnumDescriptor {
+	return file_ccx_v1_decision_proto_enumTypes[0].Descriptor()
+}
+
+func (Scope) Type() protoreflect.EnumType {
+	return &file_ccx_v1_decision_proto_enumTypes[0]
+}
+
+func (x Scope) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Scope.Descriptor instead.
+func (Scope) EnumDescriptor() ([]byte, []int) {
+	return file_ccx_v1_decision_proto_rawDescGZIP(), []int{0}
+}
+
+// Action mirrors ccxpolicy.Action. Values match the Go constants, so the zero
+// value is ACTION_NOOP as in Go.
+type Action int32
+
+const (
+	Action_ACTION_NOOP           Action = 0
+	Action_ACTION_WARN           Action = 1
+	Action_ACTION_ADJUST         Action = 2
+	Action_ACTION_CANCEL_NODE    Action = 3
+	Action_ACTION_CANCEL_SUBTREE Action = 4
+	Action_ACTION_CANCEL_ROOT    Action = 5
+	Action_ACTION_AUDIT          Action = 6
+)
+
+// Enum value maps for Action.
+var (
+	Action_name = map[int32]string{
+		0: "ACTION_NOOP",
+		1: "ACTION_WARN",
+		2: "ACTION_ADJUST",
+		3: "ACTION_CANCEL_NODE",
+		4: "ACTION_CANCEL_SUBTREE",
+		5: "ACTION_CANCEL_ROOT",
+		6: "ACTION_AUDIT",
+	}
+	Action_value = map[string]int32{
+		"ACTION_NOOP":           0,
+		"ACTION_WARN":           1,
+		"ACTION_ADJUST":         2,
+		"ACTION_CANCEL_NODE":    3,
+		"ACTION_CANCEL_SUBTREE": 4,
+		"ACTION_CANCEL_ROOT":    5,
+		"ACTION_AUDIT":          6,
+	}
+)
+
+func (x Action) Enum() *Action {
+	p := new(Action)
+	*p = x
+	return p
+}
+
+func (x Action) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Action) Descriptor() protoreflect.EnumDescriptor {
+	return file_ccx_v1_decision_proto_enumTypes[1].Descriptor()
+}
+
+func (Action) Type() protoreflect.EnumType {
+	return &file_ccx_v1_decision_proto_enumTypes[1]
+}
+
+func (x Action) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Action.Descriptor instead.
+func (Action) EnumDescriptor() ([]byte, []int) {
+	return file_ccx_v1_decision_proto_rawDescGZIP(), []int{1}
+}
+
+// ParamOpKind mirrors ccxpolicy.ParamOpKind. Values match the Go constants.
+type ParamOpKind int32
+
+const (
+	ParamOpKind_PARAM_OP_SET       ParamOpKind = 0
+	ParamOpKind_PARAM_OP_DELETE    ParamOpKind = 1
+	ParamOpKind_PARAM_OP_MERGE     ParamOpKind = 2
+	ParamOpKind_PARAM_OP_INCREMENT ParamOpKind = 3
+)
+
+// Enum value maps for ParamOpKind.
+var (
+	ParamOpKind_name = map[int32]string{
+		0: "PARAM_OP_SET",
+		1: "PARAM_OP_DELETE",
+		2: "PARAM_OP_MERGE",
+		3: "PARAM_OP_INCREMENT",
+	}
+	ParamOpKind_value = map[string]int32{
+		"PARAM_OP_SET":       0,
+		"PARAM_OP_DELETE":    1,
+		"PARAM_OP_MERGE":     2,
+		"PARAM_OP_INCREMENT": 3,
+	}
+)
+
+func (x ParamOpKind) Enum() *ParamOpKind {
+	p := new(ParamOpKind)
+	*p = x
+	return p
+}
+
+func (x ParamOpKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ParamOpKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_ccx_v1_decision_proto_enumTypes[2].Descriptor()
+}
+
+func (ParamOpKind) Type() protoreflect.EnumType {
+	return &file_ccx_v1_decision_proto_enumTypes[2]
+}
+
+func (x ParamOpKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ParamOpKind.Descriptor instead.
+func (ParamOpKind) EnumDescriptor() ([]byte, []int) {
+	return file_ccx_v1_decision_proto_rawDescGZIP(), []int{2}
+}
+
+// ParamOp is the wire form of ccxpolicy.ParamOp. value carries the Go value
+// as a JSON-like google.protobuf.Value; it is unset for a delete.
+type ParamOp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Op            ParamOpKind            `protobuf:"varint,1,opt,name=op,proto3,enum=ccx.v1.ParamOpKind" json:"op,omitempty"`
+	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Value         *structpb.Value        `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ParamOp) Reset() {
+	*x = ParamOp{}
+	mi := &file_ccx_v1_decision_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ParamOp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParamOp) ProtoMessage() {}
+
+func (x *ParamOp) ProtoReflect() protoreflect.Message {
+	mi := &file_ccx_v1_decision_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParamOp.ProtoReflect.Descriptor instead.
+func (*ParamOp) Descriptor() ([]byte, []int) {
+	return file_ccx_v1_decision_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ParamOp) GetOp() ParamOpKind {
+	if x != nil {
+		return x.Op
+	}
+	return ParamOpKind_PARAM_OP_SET
+}
+
+func (x *ParamOp) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *ParamOp) GetValue() *structpb.Value {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+// Decision is the wire form of ccxpolicy.Decision. The Adjust function cannot
+// be transported; receivers of ACTION_ADJUST must apply patch, or know how to
+// adjust when it is empty.
+type Decision struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	PolicyId string                 `protobuf:"bytes,1,opt,name=policy_id,json=policyId,proto3" json:"policy_id,omitempty"`
+	Scope    Scope                  `protobuf:"varint,2,opt,name=scope,proto3,enum=ccx.v1.Scope" json:"scope,omitempty"`
+	Action   Action                 `protobuf:"varint,3,opt,name=action,proto3,enum=ccx.v1.Action" json:"action,omitempty"`
+	// reason is the text of the Go Reason error; empty when it was nil.
+	Reason      string `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	Stop        bool   `protobuf:"varint,5,opt,name=stop,proto3" json:"stop,omitempty"`
+	EnforcerTag string `protobuf:"bytes,6,opt,name=enforcer_tag,json=enforcerTag,proto3" json:"enforcer_tag,omitempty"`
+	// patch is the declarative Patch, applied in order.
+	Patch []*ParamOp `protobuf:"bytes,7,rep,name=patch,proto3" json:"patch,omitempty"`
+	// timestamp is unset when the Go Timestamp was the zero time.
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Decision) Reset() {
+	*x = Decision{}
+	mi := &file_ccx_v1_decision_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Decision) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Decision) ProtoMessage() {}
+
+func (x *Decision) ProtoReflect() protoreflect.Message {
+	mi := &file_ccx_v1_decision_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Decision.ProtoReflect.Descriptor instead.
+func (*Decision) Descriptor() ([]byte, []int) {
+	return file_ccx_v1_decision_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Decision) GetPolicyId() string {
+	if x != nil {
+		return x.PolicyId
+	}
+	return ""
+}
+
+func (x *Decision) GetScope() Scope {
+	if x != nil {
+		return x.Scope
+	}
+	return Scope_SCOPE_NODE
+}
+
+func (x *Decision) GetAction() Action {
+	if x != nil {
+		return x.Action
+	}
+	return Action_ACTION_NOOP
+}
+
+func (x *Decision) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Decision) GetStop() bool {
+	if x != nil {
+		return x.Stop
+	}
+	return false
+}
+
+func (x *Decision) GetEnforcerTag() string {
+	if x != nil {
+		return x.EnforcerTag
+	}
+	return ""
+}
+
+func (x *Decision) GetPatch() []*ParamOp {
+	if x != nil {
+		return x.Patch
+	}
+	return nil
+}
+
+func (x *Decision) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+// DecisionList carries the ordered result of one evaluation.
+type DecisionList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Decisions     []*Decision            `protobuf:"bytes,1,rep,name=decisions,proto3" json:"decisions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DecisionList) Reset() {
+	*x = DecisionList{}
+	mi := &file_ccx_v1_decision_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DecisionList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecisionList) ProtoMessage() {}
+
+func (x *DecisionList) ProtoReflect() protoreflect.Message {
+	mi := &file_ccx_v1_decision_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecisionList.ProtoReflect.Descriptor instead.
+func (*DecisionList) Descriptor() ([]byte, []int) {
+	return file_ccx_v1_decision_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DecisionList) GetDecisions() []*Decision {
+	if x != nil {
+		return x.Decisions
+	}
+	return nil
+}
+
+var File_ccx_v1_decision_proto protoreflect.FileDescriptor
+
+const file_ccx_v1_decision_proto_rawDesc = "" +
+	"\n" +
+	"\x15ccx/v1/decision.proto\x12\x06ccx.v1\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"n\n" +
+	"\aParamOp\x12#\n" +
+	"\x02op\x18\x01 \x01(\x0e2\x13.ccx.v1.ParamOpKindR\x02op\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12,\n" +
+	"\x05value\x18\x03 \x01(\v2\x16.google.protobuf.ValueR\x05value\"\xa4\x02\n" +
+	"\bDecision\x12\x1b\n" +
+	"\tpolicy_id\x18\x01 \x01(\tR\bpolicyId\x12#\n" +
+	"\x05scope\x18\x02 \x01(\x0e2\r.ccx.v1.ScopeR\x05scope\x12&\n" +
+	"\x06action\x18\x03 \x01(\x0e2\x0e.ccx.v1.ActionR\x06action\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x12\x12\n" +
+	"\x04stop\x18\x05 \x01(\bR\x04stop\x12!\n" +
+	"\fenforcer_tag\x18\x06 \x01(\tR\venforcerTag\x12%\n" +
+	"\x05patch\x18\a \x03(\v2\x0f.ccx.v1.ParamOpR\x05patch\x128\n" +
+	"\ttimestamp\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\">\n" +
+	"\fDecisionList\x12.\n" +
+	"\tdecisions\x18\x01 \x03(\v2\x10.ccx.v1.DecisionR\tdecisions*:\n" +
+	"\x05Scope\x12\x0e\n" +
+	"\n" +
+	"SCOPE_NODE\x10\x00\x12\x11\n" +
+	"\rSCOPE_SUBTREE\x10\x01\x12\x0e\n" +
+	"\n" +
+	"SCOPE_ROOT\x10\x02*\x9a\x01\n" +
+	"\x06Action\x12\x0f\n" +
+	"\vACTION_NOOP\x10\x00\x12\x0f\n" +
+	"\vACTION_WARN\x10\x01\x12\x11\n" +
+	"\rACTION_ADJUST\x10\x02\x12\x16\n" +
+	"\x12ACTION_CANCEL_NODE\x10\x03\x12\x19\n" +
+	"\x15ACTION_CANCEL_SUBTREE\x10\x04\x12\x16\n" +
+	"\x12ACTION_CANCEL_ROOT\x10\x05\x12\x10\n" +
+	"\fACTION_AUDIT\x10\x06*`\n" +
+	"\vParamOpKind\x12\x10\n" +
+	"\fPARAM_OP_SET\x10\x00\x12\x13\n" +
+	"\x0fPARAM_OP_DELETE\x10\x01\x12\x12\n" +
+	"\x0ePARAM_OP_MERGE\x10\x02\x12\x16\n" +
+	"\x12PARAM_OP_INCREMENT\x10\x03B2Z0github.com/ArieDeha/ccxpolicy/proto/ccx/v1;ccxv1b\x06proto3"
+
+var (
+	file_ccx_v1_decision_proto_rawDescOnce sync.Once
+	file_ccx_v1_decision_proto_rawDescData []byte
+)
+
+func file_ccx_v1_decision_proto_rawDescGZIP() []byte {
+	file_ccx_v1_decision_proto_rawDescOnce.Do(func() {
+		file_ccx_v1_decision_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_ccx_v1_decision_proto_rawDesc), len(file_ccx_v1_decision_proto_rawDesc)))
+	})
+	return file_ccx_v1_decision_proto_rawDescData
+}
+
+var file_ccx_v1_decision_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_ccx_v1_decision_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_ccx_v1_decision_proto_goTypes = []any{
+	(Scope)(0),                    // 0: ccx.v1.Scope
+	(Action)(0),                   // 1: ccx.v1.Action
+	(ParamOpKind)(0),              // 2: ccx.v1.ParamOpKind
+	(*ParamOp)(nil),               // 3: ccx.v1.ParamOp
+	(*Decision)(nil),              // 4: ccx.v1.Decision
+	(*DecisionList)(nil),          // 5: ccx.v1.DecisionList
+	(*structpb.Value)(nil),        // 6: google.protobuf.Value
+	(*timestamppb.Timestamp)(nil), // 7: google.protobuf.Timestamp
+}
+var file_ccx_v1_decision_proto_depIdxs = []int32{
+	2, // 0: ccx.v1.ParamOp.op:type_name -> ccx.v1.ParamOpKind
+	6, // 1: ccx.v1.ParamOp.value:type_name -> google.protobuf.Value
+	0, // 2: ccx.v1.Decision.scope:type_name -> ccx.v1.Scope
+	1, // 3: ccx.v1.Decision.action:type_name -> ccx.v1.Action
+	3, // 4: ccx.v1.Decision.patch:type_name -> ccx.v1.ParamOp
+	7, // 5: ccx.v1.Decision.timestamp:type_name -> google.protobuf.Timestamp
+	4, // 6: ccx.v1.DecisionList.decisions:type_name -> ccx.v1.Decision
+	7, // [7:7] is the sub-list for method output_type
+	7, // [7:7] is the sub-list for method input_type
+	7, // [7:7] is the sub-list for extension type_name
+	7, // [7:7] is the sub-list for extension extendee
+	0, // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_ccx_v1_decision_proto_init() }
+func file_ccx_v1_decision_proto_init() {
+	if File_ccx_v1_decision_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_ccx_v1_decision_proto_rawDesc), len(file_ccx_v1_decision_proto_rawDesc)),
+			NumEnums:      3,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_ccx_v1_decision_proto_goTypes,
+		DependencyIndexes: file_ccx_v1_decision_proto_depIdxs,
+		EnumInfos:         file_ccx_v1_decision_proto_enumTypes,
+		MessageInfos:      file_ccx_v1_decision_proto_msgTypes,
+	}.Build()
+	File_ccx_v1_decision_proto = out.File
+	file_ccx_v1_decision_proto_goTypes = nil
+	file_ccx_v1_decision_proto_depIdxs = nil
+}
@@ -0,0 +1,132 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package policyproto converts ccxpolicy Decisions to and from their protobuf
+// form (package ccx.v1, generated into proto/ccx/v1) for transport over gRPC.
+// It lives in its own module so that the core package stays free of
+// third-party dependencies:
+//
+//	import policyproto "github.com/ArieDeha/ccxpolicy/proto"
+//
+// Regenerate the Go code after editing ccx/v1/decision.proto with
+//
+//	protoc --go_out=. --go_opt=paths=source_relative ccx/v1/decision.proto
+package policyproto
+
+import (
+	"encoding/json"
+	"errors"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+	ccxv1 "github.com/ArieDeha/ccxpolicy/proto/ccx/v1"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ToProto returns the wire form of d. The Adjust function and Metadata cannot
+// be serialised and are dropped; Reason is carried as its Error() text. Patch
+// values are carried as google.protobuf.Value: a value structpb cannot hold
+// directly is converted through its JSON encoding, and one that has none is
+// sent as null.
+func ToProto(d policy.Decision) *ccxv1.Decision {
+	p := &ccxv1.Decision{
+		PolicyId:    d.PolicyID,
+		Scope:       ccxv1.Scope(d.Scope),
+		Action:      ccxv1.Action(d.Action),
+		Stop:        d.Stop,
+		EnforcerTag: d.EnforcerTag,
+	}
+	if d.Reason != nil {
+		p.Reason = d.Reason.Error()
+	}
+	for _, op := range d.Patch {
+		o := &ccxv1.ParamOp{Op: ccxv1.ParamOpKind(op.Op), Key: op.Key}
+		if op.Value != nil || op.Op != policy.OpDelete {
+			o.Value = toValue(op.Value)
+		}
+		p.Patch = append(p.Patch, o)
+	}
+	if !d.Timestamp.IsZero() {
+		p.Timestamp = timestamppb.New(d.Timestamp)
+	}
+	return p
+}
+
+// toValue converts v to a google.protobuf.Value, falling back to v's JSON
+// encoding for types structpb does not know, and to null when that fails too.
+func toValue(v any) *structpb.Value {
+	if pv, err := structpb.NewValue(v); err == nil {
+		return pv
+	}
+	if data, err := json.Marshal(v); err == nil {
+		var generic any
+		if json.Unmarshal(data, &generic) == nil {
+			if pv, err := structpb.NewValue(generic); err == nil {
+				return pv
+			}
+		}
+	}
+	return structpb.NewNullValue()
+}
+
+// DecisionFromProto is the inverse of ToProto. A non-empty reason becomes an
+// error with that text; Adjust and Metadata are always nil. Patch values come
+// back as structpb.Value.AsInterface returns them, so numbers are float64. A
+// nil p yields the zero Decision.
+func DecisionFromProto(p *ccxv1.Decision) policy.Decision {
+	if p == nil {
+		return policy.Decision{}
+	}
+	d := policy.Decision{
+		PolicyID:    p.GetPolicyId(),
+		Scope:       policy.Scope(p.GetScope()),
+		Action:      policy.Action(p.GetAction()),
+		Stop:        p.GetStop(),
+		EnforcerTag: p.GetEnforcerTag(),
+	}
+	if r := p.GetReason(); r != "" {
+		d.Reason = errors.New(r)
+	}
+	for _, o := range p.GetPatch() {
+		op := policy.ParamOp{Op: policy.ParamOpKind(o.GetOp()), Key: o.GetKey()}
+		if o.Value != nil {
+			op.Value = o.GetValue().AsInterface()
+		}
+		d.Patch = append(d.Patch, op)
+	}
+	if ts := p.GetTimestamp(); ts != nil {
+		d.Timestamp = ts.AsTime()
+	}
+	return d
+}
+
+// ListToProto converts an ordered slice of Decisions, e.g. one Evaluate
+// result.
+func ListToProto(ds []policy.Decision) *ccxv1.DecisionList {
+	out := &ccxv1.DecisionList{Decisions: make([]*ccxv1.Decision, len(ds))}
+	for i, d := range ds {
+		out.Decisions[i] = ToProto(d)
+	}
+	return out
+}
+
+// DecisionsFromProto is the inverse of ListToProto.
+func DecisionsFromProto(l *ccxv1.DecisionList) []policy.Decision {
+	ps := l.GetDecisions()
+	out := make([]policy.Decision, len(ps))
+	for i, p := range ps {
+		out[i] = DecisionFromProto(p)
+	}
+	return out
+}
@@ -0,0 +1,106 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policyproto_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+	policyproto "github.com/ArieDeha/ccxpolicy/proto"
+	ccxv1 "github.com/ArieDeha/ccxpolicy/proto/ccx/v1"
+)
+
+func TestDecisionRoundTripOverTheWire(t *testing.T) {
+	in := []policy.Decision{
+		{PolicyID: "quota", Scope: policy.ScopeSubtree, Action: policy.ActionCancelSubtree, Reason: policy.Reason("over quota"), Stop: true},
+		{PolicyID: "audit", Action: policy.ActionAudit, EnforcerTag: "ledger"},
+	}
+
+	b, err := proto.Marshal(policyproto.ListToProto(in))
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var wire ccxv1.DecisionList
+	if err := proto.Unmarshal(b, &wire); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	out := policyproto.DecisionsFromProto(&wire)
+
+	if len(out) != len(in) {
+		t.Fatalf("got %d decisions, want %d", len(out), len(in))
+	}
+	for i := range in {
+		a, b := in[i], out[i]
+		if a.PolicyID != b.PolicyID || a.Scope != b.Scope || a.Action != b.Action ||
+			a.Stop != b.Stop || a.EnforcerTag != b.EnforcerTag {
+			t.Errorf("decision %d: got %+v, want %+v", i, b, a)
+		}
+		if (a.Reason == nil) != (b.Reason == nil) || (a.Reason != nil && a.Reason.Error() != b.Reason.Error()) {
+			t.Errorf("decision %d: reason %v, want %v", i, b.Reason, a.Reason)
+		}
+	}
+}
+
+func TestEnumValuesMatchGoConstants(t *testing.T) {
+	if int32(ccxv1.Action_ACTION_AUDIT) != int32(policy.ActionAudit) ||
+		int32(ccxv1.Action_ACTION_CANCEL_ROOT) != int32(policy.ActionCancelRoot) ||
+		int32(ccxv1.Scope_SCOPE_ROOT) != int32(policy.ScopeRoot) ||
+		int32(ccxv1.ParamOpKind_PARAM_OP_INCREMENT) != int32(policy.OpIncrement) {
+		t.Fatal("protobuf enums drifted from the Go constants")
+	}
+}
+
+func TestPatchAndTimestampSurviveTheWire(t *testing.T) {
+	in := policy.Decision{
+		PolicyID: "cap",
+		Action:   policy.ActionAdjust,
+		Patch: []policy.ParamOp{
+			{Op: policy.OpSet, Key: "q", Value: 720},
+			{Op: policy.OpSet, Key: "label", Value: ""},
+			{Op: policy.OpMerge, Key: "opts", Value: map[string]any{"fast": true}},
+			{Op: policy.OpDelete, Key: "x"},
+		},
+		Timestamp: time.Date(2025, 1, 2, 3, 4, 5, 6, time.UTC),
+	}
+	b, err := proto.Marshal(policyproto.ToProto(in))
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var wire ccxv1.Decision
+	if err := proto.Unmarshal(b, &wire); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	out := policyproto.DecisionFromProto(&wire)
+
+	want := []policy.ParamOp{
+		{Op: policy.OpSet, Key: "q", Value: 720.0},
+		{Op: policy.OpSet, Key: "label", Value: ""},
+		{Op: policy.OpMerge, Key: "opts", Value: map[string]any{"fast": true}},
+		{Op: policy.OpDelete, Key: "x"},
+	}
+	if !reflect.DeepEqual(out.Patch, want) {
+		t.Errorf("patch = %#v, want %#v", out.Patch, want)
+	}
+	if !out.Timestamp.Equal(in.Timestamp) {
+		t.Errorf("timestamp = %v, want %v", out.Timestamp, in.Timestamp)
+	}
+	if out := policyproto.DecisionFromProto(policyproto.ToProto(policy.Decision{})); !out.Timestamp.IsZero() {
+		t.Errorf("zero timestamp came back as %v", out.Timestamp)
+	}
+}
@@ -0,0 +1,23 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+module github.com/ArieDeha/ccxpolicy/proto
+
+go 1.23
+
+require github.com/ArieDeha/ccxpolicy v0.0.0
+
+require google.golang.org/protobuf v1.36.12
+
+replace github.com/ArieDeha/ccxpolicy => ../
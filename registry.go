@@ -14,69 +14,540 @@
 
 // Package ccxpolicy provides registration, evaluation, and enforcement helpers
 // for the minimal, domain-neutral policy engine. This file implements the
-// process-level policy registry, the evaluation routine that emits Decisions,
+// policy registry (instance and process-level), the evaluation routine that emits Decisions,
 // and the Enforcer interface used to apply those Decisions in a host runtime.
 package ccxpolicy
 
 import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"iter"
+	"log"
+	"slices"
 	"sort"
 	"sync"
+	"sync/atomic"
 )
 
-// registry holds process-wide policy instances in deterministic priority order.
-// It is safe for concurrent reads after initialization. Registration is
-// typically performed at process startup (e.g., in init()).
-var registry = struct {
+// Registry errors.
+var (
+	// ErrNilPolicy is returned when a nil Policy is passed for registration.
+	ErrNilPolicy = errors.New("ccxpolicy: nil policy")
+	// ErrPolicyNotFound is returned when no registered policy has the given ID.
+	ErrPolicyNotFound = errors.New("ccxpolicy: policy not found")
+	// ErrDuplicatePolicyID is returned when a policy ID is already in use.
+	ErrDuplicatePolicyID = errors.New("ccxpolicy: duplicate policy ID")
+	// ErrNilPolicyFunc is returned when a FuncPolicy lacks its match or check
+	// function.
+	ErrNilPolicyFunc = errors.New("ccxpolicy: nil policy function")
+)
+
+// DefaultPolicyCheckLimit is the maximum number of Decisions a single policy's
+// Check may contribute to one evaluation unless overridden via PolicyCheckLimit.
+const DefaultPolicyCheckLimit = 64
+
+// Registry holds a set of policy instances in deterministic priority order.
+// It is safe for concurrent use: evaluation takes a snapshot under a read
+// lock, so registrations never race with in-flight Evaluate calls.
+//
+// Evaluation holds the read lock only while the policy slice is copied, never
+// while policies run. sync.RWMutex also blocks new readers as soon as a
+// writer is waiting, so even thousands of Evaluate calls per second cannot
+// starve a registration: the writer waits at most for the copies already in
+// progress.
+//
+// Most programs use the process-wide registry through the package-level
+// functions (RegisterPolicy, Evaluate, ...). Create a dedicated Registry with
+// New when independent policy sets must coexist in one process, e.g. in a
+// library embedding ccxpolicy or in isolated test suites: its Register,
+// Evaluate, and Enforce methods never touch the global registry.
+type Registry struct {
 	mu       sync.RWMutex
 	policies []Policy
-}{}
+	version  atomic.Uint64     // see Version
+	aliases  map[string]string // former ID -> current ID, see MigratePolicy
+
+	parent   *Registry   // base layer for tenant registries; nil otherwise
+	children []*Registry // layers whose caches depend on this registry
+
+	checkLimit int
+	logf       func(format string, args ...any)
+	hooks      Hooks
+	middleware []EvaluateMiddleware // see Use
+
+	cacheMu  sync.Mutex
+	cache    map[string]cacheEntry // by node ID
+	cacheGen uint64                // bumped by every invalidation, see storeCached
+
+	subMu     sync.Mutex
+	subs      map[<-chan RegistryEvent]chan RegistryEvent
+	subBuffer int
+}
+
+// RegistryOption configures a Registry created with New.
+type RegistryOption func(*Registry)
+
+// PolicyCheckLimit caps how many Decisions each policy's Check may contribute
+// to a single evaluation. Excess decisions are dropped and a warning is logged.
+// A limit <= 0 disables the cap.
+func PolicyCheckLimit(limit int) RegistryOption {
+	return func(r *Registry) { r.checkLimit = limit }
+}
+
+// Logf sets the printf-style function used for registry diagnostics (e.g.,
+// when PolicyCheckLimit truncates a policy's output). Defaults to log.Printf;
+// nil silences diagnostics.
+func Logf(fn func(format string, args ...any)) RegistryOption {
+	return func(r *Registry) { r.logf = fn }
+}
+
+// New returns an empty Registry configured by opts.
+func New(opts ...RegistryOption) *Registry {
+	r := &Registry{
+		checkLimit: DefaultPolicyCheckLimit,
+		logf:       log.Printf,
+		cache:      map[string]cacheEntry{},
+		subBuffer:  DefaultSubscriberBuffer,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// defaultRegistry backs the package-level functions. Registration is
+// typically performed at process startup (e.g., in init()).
+var defaultRegistry = New()
+
+// Register adds a policy to r.
+//
+// Notes:
+//   - Registration order does not matter; policies are kept sorted by
+//     EffectivePriority (ascending), ties broken by ID, to ensure
+//     deterministic evaluation.
+//   - Registering clears any results memoized by CachedEvaluate.
+//   - Like http.Handle, Register panics on a configuration error: a nil p
+//     (ErrNilPolicy) or a FuncPolicy missing a function (ErrNilPolicyFunc).
+//     Registration normally happens at startup, so the mistake surfaces
+//     immediately rather than on the first evaluation.
+//   - Duplicate IDs are accepted; use RegisterStrict to reject them.
+func (r *Registry) Register(p Policy) {
+	if err := validatePolicy(p); err != nil {
+		panic(err)
+	}
+	r.mu.Lock()
+	r.policies = append(r.policies, p)
+	sort.SliceStable(r.policies, func(i, j int) bool {
+		return policyLess(r.policies[i], r.policies[j])
+	})
+	r.mu.Unlock()
+
+	r.changed(RegistryEvent{Type: PolicyAdded, PolicyID: p.ID(), Policy: p})
+}
+
+// changed records a completed mutation of r's policy set described by
+// events: it bumps the version, clears cached evaluations, and notifies
+// subscribers. Call it once per mutating call, after releasing r.mu.
+func (r *Registry) changed(events ...RegistryEvent) {
+	r.version.Add(1)
+	r.invalidateCache()
+	for _, ev := range events {
+		r.emit(ev)
+	}
+}
+
+// Version returns a counter incremented by every call that changes r's
+// policy set (Register, PromotePolicy, SwapPriorities, a committed
+// Transaction, ...). Compare two readings to detect changes without
+// subscribing to events or comparing snapshots. The counter wraps around
+// after math.MaxUint64 changes, which is safe to ignore in practice.
+//
+// For a layered registry such as a TenantRegistry the counter also advances
+// when the parent's policy set changes, since those policies are evaluated
+// too.
+func (r *Registry) Version() uint64 {
+	if r.parent != nil {
+		return r.version.Load() + r.parent.Version()
+	}
+	return r.version.Load()
+}
+
+// ReadRegistryVersion returns the global registry's Version.
+func ReadRegistryVersion() uint64 { return defaultRegistry.Version() }
+
+// policyLess orders policies by EffectivePriority, then by ID, so that the
+// evaluation order never depends on registration order.
+func policyLess(a, b Policy) bool {
+	pa, pb := EffectivePriority(a), EffectivePriority(b)
+	if pa != pb {
+		return pa < pb
+	}
+	return a.ID() < b.ID()
+}
 
 // RegisterPolicy adds a policy to the global registry.
 //
 // Notes:
 //   - Registration order does not matter; policies are kept sorted by
-//     Policy.Priority() (ascending) to ensure deterministic evaluation.
+//     EffectivePriority (ascending), ties broken by ID, to ensure
+//     deterministic evaluation.
 //   - Call this at process startup (e.g., in init()). If you hot-reload,
 //     coordinate external synchronization to avoid racing with Evaluate.
-func RegisterPolicy(p Policy) {
-	registry.mu.Lock()
-	defer registry.mu.Unlock()
+//   - Registering clears any results memoized by CachedEvaluate.
+//   - It panics on a nil p or an invalid FuncPolicy, as Registry.Register.
+func RegisterPolicy(p Policy) { defaultRegistry.Register(p) }
+
+// RegisterStrict adds p to r like Register, but returns an error instead of
+// registering a second policy with an ID already in use: one wrapping
+// ErrDuplicatePolicyID, or ErrNilPolicy / ErrNilPolicyFunc for an invalid p.
+// The check and the insertion happen under one lock, so concurrent
+// RegisterStrict calls with the same ID cannot both succeed. Only r's own
+// policies are considered, not a parent's.
+func (r *Registry) RegisterStrict(p Policy) error {
+	if err := validatePolicy(p); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	if indexPolicy(r.policies, p.ID()) >= 0 {
+		r.mu.Unlock()
+		return fmt.Errorf("%w: %q", ErrDuplicatePolicyID, p.ID())
+	}
+	r.policies = append(r.policies, p)
+	sort.SliceStable(r.policies, func(i, j int) bool {
+		return policyLess(r.policies[i], r.policies[j])
+	})
+	r.mu.Unlock()
+
+	r.changed(RegistryEvent{Type: PolicyAdded, PolicyID: p.ID(), Policy: p})
+	return nil
+}
+
+// RegisterPolicyStrict adds a policy to the global registry, rejecting
+// duplicate IDs.
+func RegisterPolicyStrict(p Policy) error { return defaultRegistry.RegisterStrict(p) }
+
+// Replace atomically swaps the policy registered under p.ID() for p, so
+// concurrent evaluations see either the old or the new policy, never both
+// or neither. It returns an error wrapping ErrPolicyNotFound if no policy
+// has that ID, or ErrNilPolicy / ErrNilPolicyFunc for an invalid p.
+// Subscribers see a PolicyReplaced event.
+func (r *Registry) Replace(p Policy) error {
+	if err := validatePolicy(p); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	i := indexPolicy(r.policies, p.ID())
+	if i < 0 {
+		r.mu.Unlock()
+		return fmt.Errorf("%w: %q", ErrPolicyNotFound, p.ID())
+	}
+	r.policies[i] = p
+	sort.SliceStable(r.policies, func(i, j int) bool {
+		return policyLess(r.policies[i], r.policies[j])
+	})
+	r.mu.Unlock()
+
+	r.changed(RegistryEvent{Type: PolicyReplaced, PolicyID: p.ID(), Policy: p})
+	return nil
+}
+
+// ReplacePolicy swaps a policy in the global registry.
+func ReplacePolicy(p Policy) error { return defaultRegistry.Replace(p) }
+
+// Unregister removes every policy registered under id (Register accepts
+// duplicates) and reports whether there was one. Aliases pointing at id (see
+// MigratePolicy) are dropped as well. Subscribers see a PolicyRemoved event
+// per removed policy.
+func (r *Registry) Unregister(id string) bool {
+	r.mu.Lock()
+	var events []RegistryEvent
+	r.policies = slices.DeleteFunc(r.policies, func(p Policy) bool {
+		if p.ID() != id {
+			return false
+		}
+		events = append(events, RegistryEvent{Type: PolicyRemoved, PolicyID: id, Policy: p})
+		return true
+	})
+	if len(events) == 0 {
+		r.mu.Unlock()
+		return false
+	}
+	for from, to := range r.aliases {
+		if to == id {
+			delete(r.aliases, from)
+		}
+	}
+	r.mu.Unlock()
+
+	r.changed(events...)
+	return true
+}
+
+// UnregisterPolicy removes a policy from the global registry.
+func UnregisterPolicy(id string) bool { return defaultRegistry.Unregister(id) }
+
+// Clear removes every policy and ID alias from r, emitting a PolicyRemoved
+// event per policy. Options, hooks, and middleware are kept. Use Reset to
+// also reset Resettable policies first.
+func (r *Registry) Clear() {
+	r.mu.Lock()
+	old := r.policies
+	r.policies = nil
+	r.aliases = nil
+	r.mu.Unlock()
+
+	events := make([]RegistryEvent, len(old))
+	for i, p := range old {
+		events[i] = RegistryEvent{Type: PolicyRemoved, PolicyID: p.ID(), Policy: p}
+	}
+	r.changed(events...)
+}
+
+// ClearPolicies removes every policy from the global registry.
+func ClearPolicies() { defaultRegistry.Clear() }
+
+// snapshot returns a copy of the registered policies taken under the read lock.
+// For a layered registry the result combines the parent's policies with r's
+// own, in priority order, with r's policies replacing parent ones of equal ID.
+func (r *Registry) snapshot() []Policy {
+	r.mu.RLock()
+	own := append([]Policy(nil), r.policies...)
+	r.mu.RUnlock()
+
+	if r.parent == nil {
+		return own
+	}
+	return overlayPolicies(r.parent.snapshot(), own)
+}
 
-	registry.policies = append(registry.policies, p)
-	sort.Slice(registry.policies, func(i, j int) bool {
-		return registry.policies[i].Priority() < registry.policies[j].Priority()
+// Policies returns a snapshot of the policies visible through r, in
+// evaluation order. The slice is a copy; callers may modify it freely.
+func (r *Registry) Policies() []Policy { return r.snapshot() }
+
+// Policies returns a snapshot of the global registry's policies.
+func Policies() []Policy { return defaultRegistry.Policies() }
+
+// EachPolicy calls fn for each policy visible through r, in evaluation order,
+// until fn returns false. It iterates a snapshot taken on entry, so fn may
+// register or unregister policies; such changes are not seen by the ongoing
+// iteration.
+func (r *Registry) EachPolicy(fn func(Policy) bool) {
+	for _, p := range r.snapshot() {
+		if !fn(p) {
+			return
+		}
+	}
+}
+
+// EachPolicy runs Registry.EachPolicy on the global registry.
+func EachPolicy(fn func(Policy) bool) { defaultRegistry.EachPolicy(fn) }
+
+// overlayPolicies merges top over base: base policies whose ID also appears in
+// top are dropped, and the result is sorted like any registry (policyLess).
+func overlayPolicies(base, top []Policy) []Policy {
+	ids := make(map[string]struct{}, len(top))
+	for _, p := range top {
+		ids[p.ID()] = struct{}{}
+	}
+	out := make([]Policy, 0, len(base)+len(top))
+	for _, p := range base {
+		if _, shadowed := ids[p.ID()]; !shadowed {
+			out = append(out, p)
+		}
+	}
+	out = append(out, top...)
+	sort.SliceStable(out, func(i, j int) bool {
+		return policyLess(out[i], out[j])
 	})
+	return out
 }
 
-// Evaluate runs all registered policies that Match(n) in ascending Priority and
-// returns the emitted Decisions in the order they should be enforced.
+// Hash returns a deterministic FNV-1a hash of the policy set in r, computed
+// from the (ID, EffectivePriority) pairs sorted by ID then priority. It
+// changes whenever a policy is added, removed, or re-prioritised, and is
+// independent of registration order. Include it in request logs to pin an evaluation to a
+// specific policy configuration.
+func (r *Registry) Hash() uint64 {
+	type entry struct {
+		id   string
+		prio float64
+	}
+	pols := r.snapshot()
+	es := make([]entry, len(pols))
+	for i, p := range pols {
+		es[i] = entry{p.ID(), EffectivePriority(p)}
+	}
+	sort.Slice(es, func(i, j int) bool {
+		if es[i].id != es[j].id {
+			return es[i].id < es[j].id
+		}
+		return es[i].prio < es[j].prio
+	})
+
+	h := fnv.New64a()
+	for _, e := range es {
+		fmt.Fprintf(h, "%q:%g;", e.id, e.prio)
+	}
+	return h.Sum64()
+}
+
+// RegistryHash returns the Hash of the global registry.
+func RegistryHash() uint64 { return defaultRegistry.Hash() }
+
+// Evaluate runs all policies in r that Match(n) in ascending priority (see
+// EffectivePriority) and returns the emitted Decisions in the order they
+// should be enforced.
 //
 // Behavior:
-//   - For each matching policy, all Decisions returned by Check(n) are appended.
+//   - For each matching policy, the Decisions returned by Check(n) are
+//     appended, truncated to the registry's PolicyCheckLimit. Decisions
+//     with an empty PolicyID get the policy's ID (see NormalizeDecisions).
 //   - If any Decision has Stop == true, evaluation short-circuits immediately
-//     and returns the decisions collected so far.
+//     and returns the decisions collected so far; Hooks.OnStop is notified.
+//   - A panic in a policy's Match or Check is recovered and replaced by a
+//     single ActionWarn Decision from that policy whose Reason is a
+//     *PanicError; evaluation continues with the next policy.
 //   - Evaluate itself is read-only and does not mutate the node.
-func Evaluate(n Node) []Decision {
-	registry.mu.RLock()
-	pols := append([]Policy(nil), registry.policies...) // snapshot under lock
-	registry.mu.RUnlock()
+//   - Middleware installed with Use wraps the whole evaluation.
+func (r *Registry) Evaluate(n Node) []Decision {
+	mw := r.currentMiddleware()
+	if len(mw) == 0 {
+		return r.evaluate(n)
+	}
+	return chainMiddleware(mw, r.evaluate)(n)
+}
 
+// evaluate is Evaluate without middleware.
+func (r *Registry) evaluate(n Node) []Decision {
 	out := make([]Decision, 0, 4)
-	for _, p := range pols {
-		if !p.Match(n) {
-			continue
+	for d := range r.EvaluateIter(n) {
+		out = append(out, d)
+	}
+	return out
+}
+
+// EvaluateIter returns an iterator over the Decisions Evaluate would return.
+// Policies are run lazily: each policy's Match and Check are called only when
+// the consumer has taken every Decision of the policies before it, so breaking
+// out of a range loop skips the remaining policies entirely. A Stop decision
+// is yielded and then ends the sequence, as in Evaluate.
+//
+// The policy set is snapshotted when iteration starts; each iteration of the
+// returned sequence re-evaluates n.
+func (r *Registry) EvaluateIter(n Node) iter.Seq[Decision] {
+	return r.evaluateSeq(n, nil)
+}
+
+// evaluateSeq implements EvaluateIter over the registered policies for which
+// keep returns true (all of them when keep is nil).
+func (r *Registry) evaluateSeq(n Node, keep func(Policy) bool) iter.Seq[Decision] {
+	return func(yield func(Decision) bool) {
+		pols := r.snapshot()
+		if keep != nil {
+			pols = slices.DeleteFunc(pols, func(p Policy) bool { return !keep(p) })
 		}
-		ds := p.Check(n)
-		for _, d := range ds {
-			out = append(out, d)
-			if d.Stop {
-				return out
+		hooks := r.currentHooks()
+
+		for i, p := range pols {
+			for _, d := range r.recoveringCheck(p, n) {
+				if !yield(d) {
+					return
+				}
+				if d.Stop {
+					if hooks.OnStop != nil {
+						hooks.OnStop(d, pols[i+1:])
+					}
+					return
+				}
 			}
 		}
 	}
-	return out
 }
 
+// recoveringCheck returns p's Decisions for n as Evaluate collects them: none
+// if p does not match, and a warning carrying a *PanicError if its Match or
+// Check panics.
+func (r *Registry) recoveringCheck(p Policy, n Node) []Decision {
+	matched, err := safeMatch(p, n)
+	if err == nil && !matched {
+		return nil
+	}
+	return r.checkOrWarn(p, err, func() []Decision { return checkPolicy(p, n) })
+}
+
+// checkOrWarn returns p's Decisions as Evaluate collects them once Match has
+// run: the warning for matchErr if Match panicked, and otherwise the output
+// of check, or the warning for its panic.
+func (r *Registry) checkOrWarn(p Policy, matchErr error, check func() []Decision) []Decision {
+	if matchErr != nil {
+		return r.panicWarning(p, matchErr)
+	}
+	ds, err := r.guardedCheck(p, check)
+	if err != nil {
+		return r.panicWarning(p, err)
+	}
+	return ds
+}
+
+// logPanic logs err, a *PanicError recovered from a policy, through r's
+// logger.
+func (r *Registry) logPanic(err error) {
+	if r.logf != nil {
+		r.logf("%v", err)
+	}
+}
+
+// panicWarning logs err, the *PanicError recovered from p, and returns the
+// warning Evaluate emits in place of p's Decisions.
+func (r *Registry) panicWarning(p Policy, err error) []Decision {
+	r.logPanic(err)
+	return []Decision{{PolicyID: p.ID(), Scope: ScopeNode, Action: ActionWarn, Reason: err}}
+}
+
+// finishCheck post-processes the output of p.Check: it fills in missing
+// policy IDs (see NormalizeDecisions) and truncates ds to the registry's
+// check limit, logging when it does.
+func (r *Registry) finishCheck(p Policy, ds []Decision) []Decision {
+	ds = NormalizeDecisions(p.ID(), ds)
+	if r.checkLimit <= 0 || len(ds) <= r.checkLimit {
+		return ds
+	}
+	if r.logf != nil {
+		r.logf("ccxpolicy: policy %q returned %d decisions; keeping the first %d",
+			p.ID(), len(ds), r.checkLimit)
+	}
+	return ds[:r.checkLimit]
+}
+
+// NormalizeDecisions sets PolicyID to policyID on every Decision in ds that
+// left it empty, so audit logs always attribute a decision to its policy.
+// ds is updated in place and returned. Evaluation applies it to the output of
+// every Check.
+func NormalizeDecisions(policyID string, ds []Decision) []Decision {
+	for i := range ds {
+		if ds[i].PolicyID == "" {
+			ds[i].PolicyID = policyID
+		}
+	}
+	return ds
+}
+
+// Evaluate runs all registered policies that Match(n) in ascending priority
+// (see EffectivePriority) and returns the emitted Decisions in the order they
+// should be enforced.
+//
+// Behavior:
+//   - For each matching policy, the Decisions returned by Check(n) are
+//     appended, at most DefaultPolicyCheckLimit per policy.
+//   - If any Decision has Stop == true, evaluation short-circuits immediately
+//     and returns the decisions collected so far.
+//   - Evaluate itself is read-only and does not mutate the node.
+func Evaluate(n Node) []Decision { return defaultRegistry.Evaluate(n) }
+
+// EvaluateIter runs Registry.EvaluateIter on the global registry.
+func EvaluateIter(n Node) iter.Seq[Decision] { return defaultRegistry.EvaluateIter(n) }
+
 // Enforcer is implemented by the host runtime to *apply* Decisions produced by
 // Evaluate. The engine is runtime-agnostic: it does not know how to cancel or
 // adjust anything—your Enforcer provides those effects.
@@ -89,39 +560,76 @@ type Enforcer interface {
 	Warn(policyID string, reason error)
 }
 
+// AuditEnforcer is an optional interface for Enforcers that keep an
+// append-only audit trail. Enforce maps ActionAudit onto Audit; Enforcers that
+// do not implement it ignore ActionAudit.
+type AuditEnforcer interface {
+	Audit(policyID string, scope Scope, reason error)
+}
+
 // Enforce applies the provided Decisions against the given Enforcer,
 // deterministically and in order.
 //
 // Mapping of Actions:
 //   - ActionNoop:         no effect
 //   - ActionWarn:         e.Warn(policyID, reason)
-//   - ActionAdjust:       e.Adjust(scope, adjustFn)  (no-op if adjustFn is nil)
+//   - ActionAdjust:       e.Adjust(scope, EffectiveAdjust(d)), i.e. Adjust
+//     then Patch (no-op if both are unset)
 //   - ActionCancelNode:   e.Cancel(ScopeNode, reason)
 //   - ActionCancelSubtree:e.Cancel(ScopeSubtree, reason)
 //   - ActionCancelRoot:   e.Cancel(ScopeRoot, reason)
+//   - ActionAudit:        e.Audit(policyID, scope, reason) if e is an
+//     AuditEnforcer, otherwise no effect
+//
+// Routing:
+//   - If e implements EnforcerRouter, each Decision is applied to the
+//     Enforcer returned by e.Route(d) (see TaggedEnforcer).
 //
 // Short-circuiting:
 //   - If a Decision has Stop == true, Enforce stops after applying it.
 func Enforce(e Enforcer, ds []Decision) {
 	for _, d := range ds {
-		switch d.Action {
-		case ActionNoop:
-			// no-op
-		case ActionWarn:
-			e.Warn(d.PolicyID, d.Reason)
-		case ActionAdjust:
-			if d.Adjust != nil {
-				e.Adjust(d.Scope, d.Adjust)
-			}
-		case ActionCancelNode:
-			e.Cancel(ScopeNode, d.Reason)
-		case ActionCancelSubtree:
-			e.Cancel(ScopeSubtree, d.Reason)
-		case ActionCancelRoot:
-			e.Cancel(ScopeRoot, d.Reason)
-		}
+		applyDecision(e, d)
 		if d.Stop {
 			return
 		}
 	}
 }
+
+// Enforce evaluates n against r's policies and applies the resulting
+// Decisions to e with the package-level Enforce. The Decisions are returned
+// for logging or auditing.
+func (r *Registry) Enforce(e Enforcer, n Node) []Decision {
+	ds := r.Evaluate(n)
+	Enforce(e, ds)
+	return ds
+}
+
+// applyDecision maps a single Decision onto e (see Enforce for the mapping).
+// If e is an EnforcerRouter, the Decision is applied to the Enforcer it
+// selects instead.
+func applyDecision(e Enforcer, d Decision) {
+	if r, ok := e.(EnforcerRouter); ok {
+		e = r.Route(d)
+	}
+	switch d.Action {
+	case ActionNoop:
+		// no-op
+	case ActionWarn:
+		e.Warn(d.PolicyID, d.Reason)
+	case ActionAdjust:
+		if fn := EffectiveAdjust(d); fn != nil {
+			e.Adjust(d.Scope, fn)
+		}
+	case ActionCancelNode:
+		e.Cancel(ScopeNode, d.Reason)
+	case ActionCancelSubtree:
+		e.Cancel(ScopeSubtree, d.Reason)
+	case ActionCancelRoot:
+		e.Cancel(ScopeRoot, d.Reason)
+	case ActionAudit:
+		if a, ok := e.(AuditEnforcer); ok {
+			a.Audit(d.PolicyID, d.Scope, d.Reason)
+		}
+	}
+}
@@ -15,8 +15,12 @@
 package ccxpolicy_test
 
 import (
+	"errors"
 	"fmt"
+	"math/rand"
 	"reflect"
+	"sync"
+	"sync/atomic"
 	"testing"
 
 	policy "github.com/ArieDeha/ccxpolicy"
@@ -91,6 +95,170 @@ func TestRegisterAndEvaluateOrderAndStop(t *testing.T) {
 	}
 }
 
+// floodPolicy returns n warn decisions from a single Check.
+type floodPolicy struct{ n int }
+
+func (floodPolicy) ID() string             { return "flood" }
+func (floodPolicy) Priority() int          { return 0 }
+func (floodPolicy) Match(policy.Node) bool { return true }
+func (p floodPolicy) Check(policy.Node) []policy.Decision {
+	ds := make([]policy.Decision, p.n)
+	for i := range ds {
+		ds[i] = policy.Decision{PolicyID: "flood", Action: policy.ActionWarn}
+	}
+	return ds
+}
+
+func TestPolicyCheckLimit(t *testing.T) {
+	n := &testNode{id: "n1", name: "N"}
+
+	r := policy.New(policy.Logf(nil))
+	r.Register(floodPolicy{n: 100})
+	if got := len(r.Evaluate(n)); got != policy.DefaultPolicyCheckLimit {
+		t.Fatalf("default limit: got %d decisions, want %d", got, policy.DefaultPolicyCheckLimit)
+	}
+
+	var logged int
+	r = policy.New(policy.PolicyCheckLimit(3), policy.Logf(func(string, ...any) { logged++ }))
+	r.Register(floodPolicy{n: 10})
+	if got := len(r.Evaluate(n)); got != 3 {
+		t.Fatalf("custom limit: got %d decisions, want 3", got)
+	}
+	if logged != 1 {
+		t.Fatalf("expected one warning when the limit is hit, got %d", logged)
+	}
+
+	r = policy.New(policy.PolicyCheckLimit(0))
+	r.Register(floodPolicy{n: 100})
+	if got := len(r.Evaluate(n)); got != 100 {
+		t.Fatalf("disabled limit: got %d decisions, want 100", got)
+	}
+}
+
+func TestRegistryHash(t *testing.T) {
+	a := policy.New()
+	a.Register(warnPolicy{id: "x", prio: 1})
+	a.Register(warnPolicy{id: "y", prio: 2})
+
+	b := policy.New()
+	b.Register(warnPolicy{id: "y", prio: 2})
+	b.Register(warnPolicy{id: "x", prio: 1})
+
+	if a.Hash() != b.Hash() {
+		t.Fatalf("hash depends on registration order")
+	}
+
+	c := policy.New()
+	c.Register(warnPolicy{id: "x", prio: 1})
+	c.Register(warnPolicy{id: "y", prio: 3})
+	if a.Hash() == c.Hash() {
+		t.Fatalf("hash ignores priority changes")
+	}
+
+	before := a.Hash()
+	a.Register(warnPolicy{id: "z", prio: 0})
+	if a.Hash() == before {
+		t.Fatalf("hash ignores added policies")
+	}
+	if policy.New().Hash() == before {
+		t.Fatalf("empty registry collides with a populated one")
+	}
+}
+
+func TestEvaluateIterStopsEarly(t *testing.T) {
+	r := policy.New()
+	first := &countingPolicy{id: "first", name: "N"}
+	second := &countingPolicy{id: "second", name: "N"}
+	r.Register(first)
+	r.Register(warnPolicy{id: "late", prio: 2})
+	r.Register(second) // same priority as first; registered later
+
+	n := &testNode{id: "n1", name: "N"}
+	for d := range r.EvaluateIter(n) {
+		if d.PolicyID == "" {
+			t.Fatalf("unexpected decision %+v", d)
+		}
+		break
+	}
+	if first.checks+second.checks != 1 {
+		t.Fatalf("breaking early must skip remaining policies; checks=%d/%d", first.checks, second.checks)
+	}
+
+	var ids []string
+	for d := range r.EvaluateIter(n) {
+		ids = append(ids, d.PolicyID)
+	}
+	if len(ids) != 3 || ids[2] != "late" {
+		t.Fatalf("full iteration = %v", ids)
+	}
+
+	r.Register(policyBStop{})
+	ids = ids[:0]
+	for d := range r.EvaluateIter(n) {
+		ids = append(ids, d.PolicyID)
+	}
+	if len(ids) != 4 || ids[3] != "B" {
+		t.Fatalf("iteration should end at the Stop decision: %v", ids)
+	}
+}
+
+// determinismPolicies returns 10 policies, several sharing a priority.
+func determinismPolicies() []policy.Policy {
+	ps := make([]policy.Policy, 10)
+	for i := range ps {
+		ps[i] = warnPolicy{id: fmt.Sprintf("det-%d", i), prio: i % 3}
+	}
+	return ps
+}
+
+func decisionIDs(ds []policy.Decision) []string {
+	ids := make([]string, len(ds))
+	for i, d := range ds {
+		ids[i] = d.PolicyID
+	}
+	return ids
+}
+
+func TestEvaluateDeterminism(t *testing.T) {
+	n := &testNode{id: "n1", name: "N"}
+	rng := rand.New(rand.NewSource(1))
+
+	var want []string
+	for run := 0; run < 100; run++ {
+		ps := determinismPolicies()
+		rng.Shuffle(len(ps), func(i, j int) { ps[i], ps[j] = ps[j], ps[i] })
+
+		r := policy.New()
+		for _, p := range ps {
+			r.Register(p)
+		}
+		got := decisionIDs(r.Evaluate(n))
+		if want == nil {
+			want = got
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("run %d: order %v differs from %v", run, got, want)
+		}
+	}
+
+	// Concurrent registration converges on the same order.
+	r := policy.New()
+	var wg sync.WaitGroup
+	for _, p := range determinismPolicies() {
+		wg.Add(1)
+		go func(p policy.Policy) {
+			defer wg.Done()
+			r.Register(p)
+			_ = r.Evaluate(n) // interleave reads with writes
+		}(p)
+	}
+	wg.Wait()
+	if got := decisionIDs(r.Evaluate(n)); !reflect.DeepEqual(got, want) {
+		t.Fatalf("concurrent registration: order %v differs from %v", got, want)
+	}
+}
+
 type recEnforcer struct {
 	adjusts []policy.Scope
 	cancels []struct {
@@ -155,6 +323,292 @@ func ExampleEnforce() {
 	policy.Enforce(demoEnf{}, ds)
 	// Output:
 	// warn W
-	// adjust 0 1080
-	// cancel 1
+	// adjust Node 1080
+	// cancel Subtree
+}
+
+func TestNegativePrioritiesRunFirst(t *testing.T) {
+	r := policy.New()
+	r.Register(warnPolicy{id: "first", prio: policy.PriorityFirst})
+	r.Register(warnPolicy{id: "minus-one", prio: -1})
+	r.Register(warnPolicy{id: "kill-switch", prio: policy.PriorityEmergency})
+
+	got := decisionIDs(r.Evaluate(&testNode{id: "n"}))
+	if want := []string{"kill-switch", "minus-one", "first"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("order = %v, want %v", got, want)
+	}
+}
+
+func TestEachPolicyStopsEarly(t *testing.T) {
+	r := policy.New()
+	r.Register(warnPolicy{id: "a", prio: 1})
+	r.Register(warnPolicy{id: "b", prio: 2})
+	r.Register(warnPolicy{id: "c", prio: 3})
+
+	var seen []string
+	r.EachPolicy(func(p policy.Policy) bool {
+		seen = append(seen, p.ID())
+		return p.ID() != "b"
+	})
+	if want := []string{"a", "b"}; !reflect.DeepEqual(seen, want) {
+		t.Fatalf("visited %v, want %v", seen, want)
+	}
+	if n := len(r.Policies()); n != 3 {
+		t.Fatalf("Policies() returned %d policies, want 3", n)
+	}
+}
+
+func TestEachPolicyAllowsMutation(t *testing.T) {
+	r := policy.New()
+	r.Register(warnPolicy{id: "a", prio: 1})
+	r.Register(warnPolicy{id: "b", prio: 2})
+
+	var seen []string
+	r.EachPolicy(func(p policy.Policy) bool {
+		seen = append(seen, p.ID())
+		r.Unregister(p.ID()) // would deadlock under the read lock
+		return true
+	})
+	if want := []string{"a", "b"}; !reflect.DeepEqual(seen, want) {
+		t.Fatalf("visited %v, want %v", seen, want)
+	}
+	if n := len(r.Policies()); n != 0 {
+		t.Fatalf("%d policies left, want 0", n)
+	}
+}
+
+func TestEvaluateFillsMissingPolicyID(t *testing.T) {
+	p, err := policy.PolicyFunc("anon", 1, func(policy.Node) bool { return true }, func(policy.Node) []policy.Decision {
+		return []policy.Decision{{Action: policy.ActionWarn}, {PolicyID: "explicit", Action: policy.ActionWarn}}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := policy.New()
+	r.Register(p)
+
+	if got, want := decisionIDs(r.Evaluate(&testNode{id: "n"})), []string{"anon", "explicit"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("policy IDs = %v, want %v", got, want)
+	}
+}
+
+// auditEnforcer records audited policy IDs next to recEnforcer's effects.
+type auditEnforcer struct {
+	recEnforcer
+	audited []string
+}
+
+func (e *auditEnforcer) Audit(policyID string, _ policy.Scope, _ error) {
+	e.audited = append(e.audited, policyID)
+}
+
+func TestEnforceActionAudit(t *testing.T) {
+	ds := []policy.Decision{
+		{PolicyID: "compliance", Action: policy.ActionAudit, Reason: policy.Reason("pii access")},
+		{PolicyID: "w", Action: policy.ActionWarn},
+	}
+
+	ae := &auditEnforcer{}
+	policy.Enforce(ae, ds)
+	if !reflect.DeepEqual(ae.audited, []string{"compliance"}) || !reflect.DeepEqual(ae.warns, []string{"w"}) {
+		t.Fatalf("audited=%v warns=%v", ae.audited, ae.warns)
+	}
+
+	plain := &recEnforcer{}
+	policy.Enforce(plain, ds)
+	if !reflect.DeepEqual(plain.warns, []string{"w"}) {
+		t.Fatalf("ActionAudit leaked into a plain enforcer: warns=%v", plain.warns)
+	}
+
+	if a, err := policy.ParseAction("audit"); err != nil || a != policy.ActionAudit {
+		t.Fatalf("ParseAction(audit) = %v, %v", a, err)
+	}
+}
+
+func TestRegistryVersion(t *testing.T) {
+	r := policy.New()
+	v0 := r.Version()
+
+	r.Register(warnPolicy{id: "a", prio: 1})
+	r.Register(warnPolicy{id: "b", prio: 2})
+	v1 := r.Version()
+	if v1 != v0+2 {
+		t.Fatalf("two registrations moved the version from %d to %d", v0, v1)
+	}
+
+	r.Evaluate(&testNode{id: "n"})
+	if r.Version() != v1 {
+		t.Fatalf("evaluation changed the version")
+	}
+	if err := r.SwapPriorities("a", "b"); err != nil {
+		t.Fatal(err)
+	}
+	if r.Version() <= v1 {
+		t.Fatalf("SwapPriorities did not bump the version")
+	}
+}
+
+func TestRegistryEnforceIsIsolated(t *testing.T) {
+	a, b := policy.New(), policy.New()
+	a.Register(warnPolicy{id: "only-a"})
+
+	e := &recEnforcer{}
+	ds := a.Enforce(e, &testNode{id: "n"})
+	if got := decisionIDs(ds); !reflect.DeepEqual(got, []string{"only-a"}) || !reflect.DeepEqual(e.warns, got) {
+		t.Fatalf("a.Enforce: decisions %v, warns %v", got, e.warns)
+	}
+
+	e = &recEnforcer{}
+	if ds := b.Enforce(e, &testNode{id: "n"}); len(ds) != 0 || len(e.warns) != 0 {
+		t.Fatalf("b saw a's policies: %v", decisionIDs(ds))
+	}
+	for _, p := range policy.Policies() {
+		if p.ID() == "only-a" {
+			t.Fatal("instance registration leaked into the global registry")
+		}
+	}
+}
+
+// panickyPolicy returns a policy that panics in Match when inMatch is set,
+// and in Check otherwise.
+func panickyPolicy(id string, prio int, inMatch bool) policy.Policy {
+	return &policy.FuncPolicy{
+		PolicyID: id,
+		Prio:     prio,
+		MatchFn: func(policy.Node) bool {
+			if inMatch {
+				panic("match " + id)
+			}
+			return true
+		},
+		CheckFn: func(policy.Node) []policy.Decision { panic("check " + id) },
+	}
+}
+
+// isPanicWarning reports whether d is the warning Evaluate emits for a
+// panicking policy.
+func isPanicWarning(d policy.Decision) bool {
+	var pe *policy.PanicError
+	return d.Action == policy.ActionWarn && errors.As(d.Reason, &pe) && pe.PolicyID == d.PolicyID
+}
+
+func TestEvaluateRecoversPolicyPanics(t *testing.T) {
+	var logged int
+	r := policy.New(policy.Logf(func(string, ...any) { logged++ }))
+	r.Register(&policy.FuncPolicy{
+		PolicyID: "boom",
+		MatchFn:  func(policy.Node) bool { return true },
+		CheckFn:  func(policy.Node) []policy.Decision { panic("boom") },
+	})
+	r.Register(warnPolicy{id: "next", prio: 1})
+
+	ds := r.Evaluate(&testNode{id: "n"})
+	if got := decisionIDs(ds); !reflect.DeepEqual(got, []string{"boom", "next"}) {
+		t.Fatalf("decisions = %v", got)
+	}
+	var pe *policy.PanicError
+	if ds[0].Action != policy.ActionWarn || !errors.As(ds[0].Reason, &pe) || pe.Value != "boom" {
+		t.Fatalf("synthetic decision = %+v", ds[0])
+	}
+	if logged != 1 {
+		t.Fatalf("expected the panic to be logged once, got %d", logged)
+	}
+}
+
+func TestUnregisterRemovesDuplicates(t *testing.T) {
+	r := policy.New()
+	r.Register(warnPolicy{id: "dup", label: "first"})
+	r.Register(warnPolicy{id: "dup", label: "second", prio: 1})
+	r.Register(warnPolicy{id: "keep", prio: 2})
+	if !r.Unregister("dup") {
+		t.Fatal("Unregister(dup) = false")
+	}
+	if got := decisionIDs(r.Evaluate(&testNode{id: "n"})); !reflect.DeepEqual(got, []string{"keep"}) {
+		t.Fatalf("after Unregister: %v", got)
+	}
+}
+
+func TestUnregisterAndClear(t *testing.T) {
+	r := policy.New()
+	r.Register(warnPolicy{id: "a"})
+	r.Register(warnPolicy{id: "b", prio: 1})
+	r.Register(warnPolicy{id: "c", prio: 2})
+	if err := r.MigratePolicy("a", "a2"); err != nil {
+		t.Fatal(err)
+	}
+	events := r.Subscribe()
+
+	if !r.Unregister("a2") {
+		t.Fatal("Unregister(a2) = false")
+	}
+	if r.Unregister("a2") || r.Unregister("missing") {
+		t.Fatal("Unregister reported removing an absent policy")
+	}
+	if ev := <-events; ev.Type != policy.PolicyRemoved || ev.PolicyID != "a2" {
+		t.Fatalf("event = %v %q", ev.Type, ev.PolicyID)
+	}
+	if _, ok := r.GetPolicy("a"); ok || len(r.PolicyAliases()) != 0 {
+		t.Fatalf("alias to removed policy kept: %v", r.PolicyAliases())
+	}
+	if got := decisionIDs(r.Evaluate(&testNode{id: "n"})); !reflect.DeepEqual(got, []string{"b", "c"}) {
+		t.Fatalf("after Unregister: %v", got)
+	}
+
+	r.Clear()
+	if len(r.Evaluate(&testNode{id: "n"})) != 0 {
+		t.Fatal("Clear left policies behind")
+	}
+	for _, want := range []string{"b", "c"} {
+		if ev := <-events; ev.Type != policy.PolicyRemoved || ev.PolicyID != want {
+			t.Fatalf("event = %v %q, want Removed %q", ev.Type, ev.PolicyID, want)
+		}
+	}
+}
+
+func TestRegisterStrictAndReplace(t *testing.T) {
+	r := policy.New()
+	if err := r.RegisterStrict(warnPolicy{id: "a", label: "v1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.RegisterStrict(warnPolicy{id: "a", label: "v2"}); !errors.Is(err, policy.ErrDuplicatePolicyID) {
+		t.Fatalf("duplicate: %v", err)
+	}
+	if err := r.RegisterStrict(nil); !errors.Is(err, policy.ErrNilPolicy) {
+		t.Fatalf("nil: %v", err)
+	}
+	r.Register(warnPolicy{id: "b", prio: 5})
+
+	events := r.Subscribe()
+	if err := r.Replace(warnPolicy{id: "a", label: "v2", prio: 10}); err != nil {
+		t.Fatal(err)
+	}
+	if ev := <-events; ev.Type != policy.PolicyReplaced || ev.PolicyID != "a" {
+		t.Fatalf("event = %v %q", ev.Type, ev.PolicyID)
+	}
+	ds := r.Evaluate(&testNode{id: "n"})
+	if got := decisionIDs(ds); !reflect.DeepEqual(got, []string{"b", "a"}) || ds[1].Reason.Error() != "v2" {
+		t.Fatalf("after Replace: %v", ds)
+	}
+	if err := r.Replace(warnPolicy{id: "missing"}); !errors.Is(err, policy.ErrPolicyNotFound) {
+		t.Fatalf("Replace(missing) = %v", err)
+	}
+}
+
+func TestRegisterStrictConcurrent(t *testing.T) {
+	r := policy.New()
+	var wg sync.WaitGroup
+	var ok atomic.Int32
+	for range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if r.RegisterStrict(warnPolicy{id: "same"}) == nil {
+				ok.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if ok.Load() != 1 || len(r.Policies()) != 1 {
+		t.Fatalf("%d registrations succeeded, %d policies", ok.Load(), len(r.Policies()))
+	}
 }
@@ -0,0 +1,178 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// RegistryTransaction stages policy changes for Registry.Transaction. Each
+// method validates the change against the staged state and returns an error
+// if it cannot be applied; nothing is visible to evaluations until the
+// transaction commits.
+type RegistryTransaction struct {
+	policies []Policy // staged view of the registry's own policies
+	ops      []registryOp
+}
+
+// registryOp is one staged change, replayed at commit.
+type registryOp struct {
+	typ RegistryEventType
+	id  string
+	p   Policy
+}
+
+// Register stages the addition of p. It returns ErrNilPolicy or
+// ErrNilPolicyFunc for an invalid p.
+func (tx *RegistryTransaction) Register(p Policy) error {
+	return tx.stage(registryOp{typ: PolicyAdded, p: p})
+}
+
+// Deregister stages the removal of every policy with the given ID, like
+// Registry.Unregister. It returns an error wrapping ErrPolicyNotFound if no
+// such policy is staged.
+func (tx *RegistryTransaction) Deregister(id string) error {
+	return tx.stage(registryOp{typ: PolicyRemoved, id: id})
+}
+
+// Replace stages swapping every policy with p.ID() for p. It returns an
+// error wrapping ErrPolicyNotFound if no such policy is staged.
+func (tx *RegistryTransaction) Replace(p Policy) error {
+	return tx.stage(registryOp{typ: PolicyReplaced, p: p})
+}
+
+// Policies returns a copy of the staged policies (the registry's own
+// policies with the changes staged so far applied), in evaluation order.
+func (tx *RegistryTransaction) Policies() []Policy {
+	return append([]Policy(nil), tx.policies...)
+}
+
+func (tx *RegistryTransaction) stage(op registryOp) error {
+	policies, _, err := applyRegistryOps(tx.policies, []registryOp{op})
+	if err != nil {
+		return err
+	}
+	tx.policies = policies
+	tx.ops = append(tx.ops, op)
+	return nil
+}
+
+// applyRegistryOps applies ops to a copy of policies and returns the sorted
+// result together with the events describing it.
+func applyRegistryOps(policies []Policy, ops []registryOp) ([]Policy, []RegistryEvent, error) {
+	out := append([]Policy(nil), policies...)
+	events := make([]RegistryEvent, 0, len(ops))
+	for _, op := range ops {
+		switch op.typ {
+		case PolicyAdded:
+			if err := validatePolicy(op.p); err != nil {
+				return nil, nil, err
+			}
+			out = append(out, op.p)
+			events = append(events, RegistryEvent{Type: PolicyAdded, PolicyID: op.p.ID(), Policy: op.p})
+		case PolicyRemoved:
+			n := len(events)
+			out = slices.DeleteFunc(out, func(p Policy) bool {
+				if p.ID() != op.id {
+					return false
+				}
+				events = append(events, RegistryEvent{Type: PolicyRemoved, PolicyID: op.id, Policy: p})
+				return true
+			})
+			if len(events) == n {
+				return nil, nil, fmt.Errorf("%w: %q", ErrPolicyNotFound, op.id)
+			}
+		case PolicyReplaced:
+			if err := validatePolicy(op.p); err != nil {
+				return nil, nil, err
+			}
+			n := len(events)
+			for i, p := range out {
+				if p.ID() == op.p.ID() {
+					out[i] = op.p
+					events = append(events, RegistryEvent{Type: PolicyReplaced, PolicyID: op.p.ID(), Policy: op.p})
+				}
+			}
+			if len(events) == n {
+				return nil, nil, fmt.Errorf("%w: %q", ErrPolicyNotFound, op.p.ID())
+			}
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return policyLess(out[i], out[j])
+	})
+	return out, events, nil
+}
+
+// indexPolicy returns the index of the first policy in ps with the given ID,
+// or -1.
+func indexPolicy(ps []Policy, id string) int {
+	for i, p := range ps {
+		if p.ID() == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// Transaction applies several registry changes atomically. fn stages changes
+// on a RegistryTransaction; when it returns nil, they are replayed in order
+// against the current policies and swapped in under a single write lock, so
+// concurrent evaluations see either none or all of them. If fn returns an
+// error, nothing is applied and that error is returned.
+//
+// Policies registered concurrently while fn runs are kept. Should a staged
+// change no longer apply at commit (e.g. another goroutine removed the policy
+// being replaced), the transaction is abandoned and the error returned.
+// On commit the cache is cleared and, as with the direct methods, one
+// RegistryEvent is emitted per policy a change affected; aliases pointing
+// at a removed ID are dropped.
+// Only r's own policies are visible to a transaction, not a parent's.
+func (r *Registry) Transaction(fn func(*RegistryTransaction) error) error {
+	r.mu.RLock()
+	tx := &RegistryTransaction{policies: append([]Policy(nil), r.policies...)}
+	r.mu.RUnlock()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if len(tx.ops) == 0 {
+		return nil
+	}
+
+	r.mu.Lock()
+	policies, events, err := applyRegistryOps(r.policies, tx.ops)
+	if err == nil {
+		r.policies = policies
+		for _, ev := range events {
+			if ev.Type == PolicyRemoved && indexPolicy(policies, ev.PolicyID) < 0 {
+				for from, to := range r.aliases {
+					if to == ev.PolicyID {
+						delete(r.aliases, from)
+					}
+				}
+			}
+		}
+	}
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	r.changed(events...)
+	return nil
+}
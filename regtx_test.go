@@ -0,0 +1,109 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+func TestRegistryTransactionCommits(t *testing.T) {
+	r := policy.New()
+	r.Register(warnPolicy{id: "old", prio: 1})
+	r.Register(warnPolicy{id: "keep", prio: 2})
+	events := r.Subscribe()
+
+	err := r.Transaction(func(tx *policy.RegistryTransaction) error {
+		if err := tx.Register(warnPolicy{id: "new", prio: 3}); err != nil {
+			return err
+		}
+		if err := tx.Deregister("old"); err != nil {
+			return err
+		}
+		var staged []string
+		for _, p := range tx.Policies() {
+			staged = append(staged, p.ID())
+		}
+		if want := []string{"keep", "new"}; !reflect.DeepEqual(staged, want) {
+			t.Errorf("staged = %v, want %v", staged, want)
+		}
+		return tx.Replace(warnPolicy{id: "keep", prio: 4})
+	})
+	if err != nil {
+		t.Fatalf("Transaction: %v", err)
+	}
+
+	if got, want := decisionIDs(r.Evaluate(&testNode{id: "n"})), []string{"new", "keep"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("policies after commit = %v, want %v", got, want)
+	}
+	var types []policy.RegistryEventType
+	for len(events) > 0 {
+		types = append(types, (<-events).Type)
+	}
+	if want := []policy.RegistryEventType{policy.PolicyAdded, policy.PolicyRemoved, policy.PolicyReplaced}; !reflect.DeepEqual(types, want) {
+		t.Fatalf("events = %v, want %v", types, want)
+	}
+}
+
+func TestRegistryTransactionRollsBack(t *testing.T) {
+	r := policy.New()
+	r.Register(warnPolicy{id: "a", prio: 1})
+
+	errAbort := errors.New("abort")
+	err := r.Transaction(func(tx *policy.RegistryTransaction) error {
+		if err := tx.Deregister("a"); err != nil {
+			return err
+		}
+		if err := tx.Deregister("a"); !errors.Is(err, policy.ErrPolicyNotFound) {
+			t.Errorf("second Deregister: got %v, want ErrPolicyNotFound", err)
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("Transaction: got %v", err)
+	}
+	if got := decisionIDs(r.Evaluate(&testNode{id: "n"})); !reflect.DeepEqual(got, []string{"a"}) {
+		t.Fatalf("aborted transaction changed the registry: %v", got)
+	}
+}
+
+func TestRegistryTransactionAffectsEveryDuplicate(t *testing.T) {
+	r := policy.New()
+	r.Register(warnPolicy{id: "a", prio: 1})
+	r.Register(warnPolicy{id: "a", prio: 2})
+	r.Register(warnPolicy{id: "b", prio: 3})
+	r.Register(warnPolicy{id: "b", prio: 4})
+
+	err := r.Transaction(func(tx *policy.RegistryTransaction) error {
+		if err := tx.Deregister("a"); err != nil {
+			return err
+		}
+		return tx.Replace(warnPolicy{id: "b", prio: 5})
+	})
+	if err != nil {
+		t.Fatalf("Transaction: %v", err)
+	}
+	if got, want := decisionIDs(r.Evaluate(&testNode{id: "n"})), []string{"b", "b"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("policies after commit = %v, want %v", got, want)
+	}
+	for _, p := range r.Policies() {
+		if p.Priority() != 5 {
+			t.Fatalf("policy %q kept priority %d after Replace", p.ID(), p.Priority())
+		}
+	}
+}
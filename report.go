@@ -0,0 +1,66 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// FormatReport renders a human-readable report of the Decisions produced for n.
+// See WriteReport for the layout.
+func FormatReport(n Node, ds []Decision) string {
+	var b strings.Builder
+	_ = WriteReport(&b, n, ds) // strings.Builder never fails
+	return b.String()
+}
+
+// WriteReport writes a table-like report of ds to w: a header line naming the
+// node, followed by one aligned row per Decision with the columns
+// POLICY | ACTION | SCOPE | REASON. Decisions with Stop set are marked with
+// "(stop)" after the action. An empty ds is reported as "(no decisions)".
+//
+// Example:
+//
+//	node n1 (Transcode): 2 decision(s)
+//	POLICY       ACTION             SCOPE    REASON
+//	cap_quality  Adjust             Subtree  quality above cap
+//	safety       CancelRoot (stop)  Root     safety override
+func WriteReport(w io.Writer, n Node, ds []Decision) error {
+	if _, err := fmt.Fprintf(w, "node %s (%s): %d decision(s)\n", n.ID(), n.Name(), len(ds)); err != nil {
+		return err
+	}
+	if len(ds) == 0 {
+		_, err := io.WriteString(w, "(no decisions)\n")
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "POLICY\tACTION\tSCOPE\tREASON")
+	for _, d := range ds {
+		action := d.Action.String()
+		if d.Stop {
+			action += " (stop)"
+		}
+		reason := "-"
+		if d.Reason != nil {
+			reason = d.Reason.Error()
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", d.PolicyID, action, d.Scope, reason)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,46 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"fmt"
+	"testing"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+func TestFormatReportEmpty(t *testing.T) {
+	got := policy.FormatReport(&testNode{id: "n1", name: "N"}, nil)
+	if want := "node n1 (N): 0 decision(s)\n(no decisions)\n"; got != want {
+		t.Fatalf("FormatReport = %q, want %q", got, want)
+	}
+}
+
+// ExampleFormatReport renders decisions as an aligned table.
+func ExampleFormatReport() {
+	n := &testNode{id: "n1", name: "Transcode"}
+	ds := []policy.Decision{
+		{PolicyID: "cap_quality", Action: policy.ActionAdjust, Scope: policy.ScopeSubtree, Reason: policy.Reason("quality above cap")},
+		{PolicyID: "audit", Action: policy.ActionWarn},
+		{PolicyID: "safety", Action: policy.ActionCancelRoot, Scope: policy.ScopeRoot, Reason: policy.Reason("safety override"), Stop: true},
+	}
+	fmt.Print(policy.FormatReport(n, ds))
+	// Output:
+	// node n1 (Transcode): 3 decision(s)
+	// POLICY       ACTION             SCOPE    REASON
+	// cap_quality  Adjust             Subtree  quality above cap
+	// audit        Warn               Node     -
+	// safety       CancelRoot (stop)  Root     safety override
+}
@@ -0,0 +1,87 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Resettable is an optional interface for stateful policies (rate limiters,
+// counters, caches) whose accumulated state can be discarded, typically
+// between tests. The stateful built-in policies implement it.
+type Resettable interface {
+	Reset()
+}
+
+// resetPolicy calls p.Reset if p is Resettable.
+func resetPolicy(p Policy) {
+	if rp, ok := asPolicy[Resettable](p); ok {
+		rp.Reset()
+	}
+}
+
+// ResetAllPolicies calls Reset on every policy registered in r that
+// implements Resettable. The policy set itself is unchanged. On a
+// TenantRegistry only the tenant's own policies are reset: the parent's are
+// shared with every other tenant, so reset them through the parent.
+func (r *Registry) ResetAllPolicies() {
+	r.mu.RLock()
+	own := slices.Clone(r.policies)
+	r.mu.RUnlock()
+	for _, p := range own {
+		resetPolicy(p)
+	}
+}
+
+// ResetAllPolicies runs Registry.ResetAllPolicies against the global
+// registry.
+func ResetAllPolicies() { defaultRegistry.ResetAllPolicies() }
+
+// ResetPolicy calls Reset on the policy registered in r under id (aliases
+// left by MigratePolicy are followed) if it implements Resettable. It returns
+// an error wrapping ErrPolicyNotFound if r itself has no such policy; as with
+// ResetAllPolicies, a TenantRegistry does not reach its parent's policies.
+func (r *Registry) ResetPolicy(id string) error {
+	r.mu.RLock()
+	target := id
+	if to, ok := r.aliases[id]; ok {
+		target = to
+	}
+	var p Policy
+	if i := indexPolicy(r.policies, target); i >= 0 {
+		p = r.policies[i]
+	}
+	r.mu.RUnlock()
+	if p == nil {
+		return fmt.Errorf("%w: %q", ErrPolicyNotFound, id)
+	}
+	resetPolicy(p)
+	return nil
+}
+
+// ResetPolicy runs Registry.ResetPolicy against the global registry.
+func ResetPolicy(id string) error { return defaultRegistry.ResetPolicy(id) }
+
+// Reset resets every Resettable policy in r and then removes all policies
+// and ID aliases like Clear. It exists mainly to isolate tests that share a
+// registry.
+func (r *Registry) Reset() {
+	r.ResetAllPolicies()
+	r.Clear()
+}
+
+// ResetRegistry runs Registry.Reset against the global registry.
+func ResetRegistry() { defaultRegistry.Reset() }
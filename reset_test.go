@@ -0,0 +1,111 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+// resettablePolicy counts its Reset calls.
+type resettablePolicy struct {
+	warnPolicy
+	resets int
+}
+
+func (p *resettablePolicy) Reset() { p.resets++ }
+
+func TestResetPolicies(t *testing.T) {
+	r := policy.New()
+	throttle := policy.NewThrottleWarnPolicy("throttle", 0, 1, time.Hour)
+	memo := policy.MemoizedPolicy(policy.NewThrottleWarnPolicy("memo", 1, 0, time.Hour), func(policy.Node) string { return "k" })
+	custom := &resettablePolicy{warnPolicy: warnPolicy{id: "custom", prio: 2}}
+	r.Register(throttle)
+	r.Register(memo)
+	r.Register(custom)
+	r.Register(warnPolicy{id: "plain", prio: 3})
+
+	n := &testNode{id: "n"}
+	r.Evaluate(n)
+	if got := decisionIDs(r.Evaluate(n)); len(got) != 4 {
+		t.Fatalf("expected throttle to fire on second evaluation, got %v", got)
+	}
+
+	r.ResetAllPolicies()
+	if custom.resets != 1 {
+		t.Fatalf("custom policy reset %d times", custom.resets)
+	}
+	// The throttle forgot its count; the memoized throttle re-runs and fires
+	// again on its first check (max 0).
+	if got := decisionIDs(r.Evaluate(n)); len(got) != 3 || got[0] != "memo" {
+		t.Fatalf("after reset got %v", got)
+	}
+
+	if err := r.ResetPolicy("custom"); err != nil || custom.resets != 2 {
+		t.Fatalf("ResetPolicy: %v (resets %d)", err, custom.resets)
+	}
+	if err := r.ResetPolicy("plain"); err != nil {
+		t.Fatalf("ResetPolicy on non-resettable: %v", err)
+	}
+	if err := r.ResetPolicy("missing"); !errors.Is(err, policy.ErrPolicyNotFound) {
+		t.Fatalf("ResetPolicy(missing) = %v", err)
+	}
+}
+
+func TestRegistryReset(t *testing.T) {
+	r := policy.New()
+	custom := &resettablePolicy{warnPolicy: warnPolicy{id: "custom"}}
+	r.Register(custom)
+	r.Register(warnPolicy{id: "plain", prio: 1})
+	events := r.Subscribe()
+	before := r.Version()
+
+	r.Reset()
+	if custom.resets != 1 {
+		t.Fatalf("policy not reset before removal")
+	}
+	if len(r.Policies()) != 0 || r.Version() == before {
+		t.Fatalf("registry not cleared: %d policies", len(r.Policies()))
+	}
+	for _, want := range []string{"custom", "plain"} {
+		if ev := <-events; ev.Type != policy.PolicyRemoved || ev.PolicyID != want {
+			t.Fatalf("event %v %q, want Removed %q", ev.Type, ev.PolicyID, want)
+		}
+	}
+}
+
+func TestTenantResetLeavesSharedPoliciesAlone(t *testing.T) {
+	shared := &resettablePolicy{warnPolicy: warnPolicy{id: "reset-shared"}}
+	policy.RegisterPolicy(shared)
+	defer policy.UnregisterPolicy("reset-shared")
+	tenant := policy.TenantRegistry("reset-tenant")
+	own := &resettablePolicy{warnPolicy: warnPolicy{id: "reset-own"}}
+	tenant.Register(own)
+	defer tenant.Unregister("reset-own")
+
+	tenant.ResetAllPolicies()
+	if shared.resets != 0 || own.resets != 1 {
+		t.Fatalf("resets: shared=%d own=%d", shared.resets, own.resets)
+	}
+	if err := tenant.ResetPolicy("reset-shared"); !errors.Is(err, policy.ErrPolicyNotFound) {
+		t.Fatalf("ResetPolicy reached the parent: %v", err)
+	}
+	if shared.resets != 0 {
+		t.Fatalf("shared policy reset through a tenant")
+	}
+}
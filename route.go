@@ -0,0 +1,143 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+)
+
+// EnforcerRouter is an optional interface for Enforcers that delegate each
+// Decision to another Enforcer. Enforce (and the other Enforce* helpers)
+// consult Route before applying a Decision, since the plain Enforcer methods
+// do not carry the Decision itself.
+type EnforcerRouter interface {
+	Enforcer
+	// Route returns the Enforcer that should apply d. It must not return nil.
+	Route(d Decision) Enforcer
+}
+
+// taggedEnforcer routes decisions by Decision.EnforcerTag.
+type taggedEnforcer struct {
+	Enforcer // fallback; also serves direct method calls
+	tags     map[string]Enforcer
+}
+
+// TaggedEnforcer returns an Enforcer that applies each Decision with the
+// Enforcer registered under its EnforcerTag in tags. Decisions with an empty
+// or unknown tag go to fallback, as do direct calls to the returned
+// Enforcer's Adjust/Cancel/Warn methods. A nil fallback discards them.
+//
+// This lets a host that manages several independent subsystems (e.g., a
+// "video-encoder" and a "storage" enforcer) route decisions without a central
+// switch. The tags map is copied.
+func TaggedEnforcer(tags map[string]Enforcer, fallback Enforcer) Enforcer {
+	cp := make(map[string]Enforcer, len(tags))
+	for k, v := range tags {
+		cp[k] = v
+	}
+	if fallback == nil {
+		fallback = discardEnforcer{}
+	}
+	return taggedEnforcer{Enforcer: fallback, tags: cp}
+}
+
+// discardEnforcer ignores every Decision.
+type discardEnforcer struct{}
+
+func (discardEnforcer) Adjust(Scope, func(map[string]any)) {}
+func (discardEnforcer) Cancel(Scope, error)                {}
+func (discardEnforcer) Warn(string, error)                 {}
+
+// Route implements EnforcerRouter. An Enforcer selected for d that is itself
+// an EnforcerRouter, such as a TeeEnforcer, routes d in turn.
+func (t taggedEnforcer) Route(d Decision) Enforcer {
+	e := t.Enforcer
+	if te, ok := t.tags[d.EnforcerTag]; ok && d.EnforcerTag != "" {
+		e = te
+	}
+	if r, ok := e.(EnforcerRouter); ok {
+		return r.Route(d)
+	}
+	return e
+}
+
+// Commit implements CommitEnforcer by committing every tagged Enforcer, in
+// tag order, and then the fallback. An Enforcer registered under several
+// tags is committed once. The errors are joined.
+func (t taggedEnforcer) Commit() error {
+	tags := make([]string, 0, len(t.tags))
+	for tag := range t.tags {
+		tags = append(tags, tag)
+	}
+	slices.Sort(tags)
+	var (
+		errs []error
+		done []Enforcer
+	)
+	commit := func(e Enforcer) {
+		if e == nil {
+			return
+		}
+		canCompare := reflect.TypeOf(e).Comparable()
+		if canCompare && slices.Contains(done, e) {
+			return
+		}
+		if canCompare {
+			done = append(done, e)
+		}
+		errs = append(errs, commitEnforcer(e))
+	}
+	for _, tag := range tags {
+		commit(t.tags[tag])
+	}
+	commit(t.Enforcer)
+	return errors.Join(errs...)
+}
+
+// teeEnforcer copies each routed Decision to a channel before delegating.
+type teeEnforcer struct {
+	inner Enforcer
+	tap   chan<- Decision
+}
+
+// TeeEnforcer wraps inner so that every Decision processed through Enforce
+// (or any helper honouring EnforcerRouter) is also sent to tap, e.g. to
+// stream decisions to a dashboard. Sends never block: when tap is full the
+// copy is dropped, so observation adds no latency to enforcement. Direct
+// calls to the returned Enforcer's methods go to inner without being tapped.
+func TeeEnforcer(inner Enforcer, tap chan<- Decision) Enforcer {
+	return teeEnforcer{inner: inner, tap: tap}
+}
+
+// Route implements EnforcerRouter so the full Decision can be tapped.
+func (e teeEnforcer) Route(d Decision) Enforcer {
+	select {
+	case e.tap <- d:
+	default:
+	}
+	if r, ok := e.inner.(EnforcerRouter); ok {
+		return r.Route(d)
+	}
+	return e.inner
+}
+
+func (e teeEnforcer) Adjust(scope Scope, fn func(map[string]any)) { e.inner.Adjust(scope, fn) }
+func (e teeEnforcer) Cancel(scope Scope, reason error)            { e.inner.Cancel(scope, reason) }
+func (e teeEnforcer) Warn(policyID string, reason error)          { e.inner.Warn(policyID, reason) }
+
+// Commit implements CommitEnforcer by committing inner, if it supports it.
+func (e teeEnforcer) Commit() error { return commitEnforcer(e.inner) }
@@ -0,0 +1,102 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"reflect"
+	"testing"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+func TestTaggedEnforcer(t *testing.T) {
+	video, fallback := &recEnforcer{}, &recEnforcer{}
+	e := policy.TaggedEnforcer(map[string]policy.Enforcer{"video-encoder": video}, fallback)
+
+	policy.Enforce(e, []policy.Decision{
+		{PolicyID: "v", Action: policy.ActionWarn, EnforcerTag: "video-encoder"},
+		{PolicyID: "u", Action: policy.ActionWarn, EnforcerTag: "unknown"},
+		{PolicyID: "d", Action: policy.ActionWarn},
+		{PolicyID: "c", Action: policy.ActionCancelNode, EnforcerTag: "video-encoder"},
+	})
+
+	if !reflect.DeepEqual(video.warns, []string{"v"}) || len(video.cancels) != 1 {
+		t.Fatalf("video enforcer got warns=%v cancels=%v", video.warns, video.cancels)
+	}
+	if !reflect.DeepEqual(fallback.warns, []string{"u", "d"}) || len(fallback.cancels) != 0 {
+		t.Fatalf("fallback enforcer got warns=%v cancels=%v", fallback.warns, fallback.cancels)
+	}
+
+	e.Warn("direct", nil)
+	if fallback.warns[len(fallback.warns)-1] != "direct" {
+		t.Fatalf("direct calls should reach the fallback")
+	}
+}
+
+func TestTaggedEnforcerNilFallback(t *testing.T) {
+	video := &recEnforcer{}
+	e := policy.TaggedEnforcer(map[string]policy.Enforcer{"video-encoder": video}, nil)
+	policy.Enforce(e, []policy.Decision{
+		{PolicyID: "u", Action: policy.ActionWarn, EnforcerTag: "unknown"},
+		{PolicyID: "v", Action: policy.ActionWarn, EnforcerTag: "video-encoder"},
+	})
+	e.Cancel(policy.ScopeNode, nil)
+	if !reflect.DeepEqual(video.warns, []string{"v"}) {
+		t.Fatalf("video enforcer got warns=%v", video.warns)
+	}
+}
+
+func TestTeeEnforcerDropsWhenFull(t *testing.T) {
+	inner := &recEnforcer{}
+	tap := make(chan policy.Decision, 1)
+	ds := []policy.Decision{
+		{PolicyID: "A", Action: policy.ActionWarn},
+		{PolicyID: "B", Action: policy.ActionWarn},
+	}
+	policy.Enforce(policy.TeeEnforcer(inner, tap), ds)
+
+	if want := []string{"A", "B"}; !reflect.DeepEqual(inner.warns, want) {
+		t.Fatalf("inner warns = %v, want %v", inner.warns, want)
+	}
+	if got := <-tap; got.PolicyID != "A" {
+		t.Fatalf("tapped %q, want A", got.PolicyID)
+	}
+	if len(tap) != 0 {
+		t.Fatalf("overflowing decision was not dropped")
+	}
+}
+
+func TestTaggedEnforcerRoutesNestedRouters(t *testing.T) {
+	video := &recEnforcer{}
+	tap := make(chan policy.Decision, 1)
+	audit := &sliceConsumer{}
+	e := policy.TaggedEnforcer(map[string]policy.Enforcer{
+		"video":  policy.TeeEnforcer(video, tap),
+		"ledger": policy.AdapterEnforcer(audit),
+	}, nil)
+
+	ds := []policy.Decision{
+		{PolicyID: "v", Action: policy.ActionWarn, EnforcerTag: "video"},
+		{PolicyID: "l", Action: policy.ActionAudit, EnforcerTag: "ledger", Reason: policy.Reason("kept")},
+	}
+	policy.Enforce(e, ds)
+
+	if !reflect.DeepEqual(video.warns, []string{"v"}) || len(tap) != 1 || (<-tap).PolicyID != "v" {
+		t.Fatalf("tee under a tag: warns=%v, tapped %d", video.warns, len(tap))
+	}
+	if len(audit.got) != 1 || !reflect.DeepEqual(audit.got[0], ds[1]) {
+		t.Fatalf("adapter under a tag got %+v, want the original decision", audit.got)
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+import (
+	"context"
+	"log/slog"
+)
+
+// DefaultSlogLevel maps an Action to the level SlogEvaluate logs it at:
+// Debug for Noop, Info for Adjust and Audit, Warn for Warn, and Error for the
+// Cancel* actions.
+func DefaultSlogLevel(a Action) slog.Level {
+	switch a {
+	case ActionNoop:
+		return slog.LevelDebug
+	case ActionWarn:
+		return slog.LevelWarn
+	case ActionCancelNode, ActionCancelSubtree, ActionCancelRoot:
+		return slog.LevelError
+	}
+	return slog.LevelInfo
+}
+
+// SlogEvaluate runs Evaluate(n) and logs each returned Decision as a
+// "policy decision" record with the attributes policy.id, decision.action,
+// decision.scope, and decision.reason (empty for a nil Reason), at the level
+// chosen by DefaultSlogLevel. A nil logger means slog.Default(). The
+// Decisions are returned unchanged.
+func (r *Registry) SlogEvaluate(ctx context.Context, logger *slog.Logger, n Node) []Decision {
+	return r.SlogEvaluateLevels(ctx, logger, n, DefaultSlogLevel)
+}
+
+// SlogEvaluateLevels is SlogEvaluate with the level of each record chosen by
+// level instead of DefaultSlogLevel.
+func (r *Registry) SlogEvaluateLevels(ctx context.Context, logger *slog.Logger, n Node, level func(Action) slog.Level) []Decision {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	ds := r.Evaluate(n)
+	for _, d := range ds {
+		reason := ""
+		if d.Reason != nil {
+			reason = d.Reason.Error()
+		}
+		logger.LogAttrs(ctx, level(d.Action), "policy decision",
+			slog.String("policy.id", d.PolicyID),
+			slog.String("decision.action", d.Action.String()),
+			slog.String("decision.scope", d.Scope.String()),
+			slog.String("decision.reason", reason),
+		)
+	}
+	return ds
+}
+
+// SlogEvaluate runs Registry.SlogEvaluate against the global registry.
+func SlogEvaluate(ctx context.Context, logger *slog.Logger, n Node) []Decision {
+	return defaultRegistry.SlogEvaluate(ctx, logger, n)
+}
+
+// SlogEvaluateLevels runs Registry.SlogEvaluateLevels against the global
+// registry.
+func SlogEvaluateLevels(ctx context.Context, logger *slog.Logger, n Node, level func(Action) slog.Level) []Decision {
+	return defaultRegistry.SlogEvaluateLevels(ctx, logger, n, level)
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+func TestSlogEvaluate(t *testing.T) {
+	r := policy.New()
+	r.Register(warnPolicy{id: "w", label: "too hot"})
+	r.Register(&policy.FuncPolicy{
+		PolicyID: "c",
+		Prio:     1,
+		MatchFn:  func(policy.Node) bool { return true },
+		CheckFn: func(policy.Node) []policy.Decision {
+			return []policy.Decision{{Action: policy.ActionCancelNode, Reason: policy.Reason("stop")}}
+		},
+	})
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	ds := r.SlogEvaluate(context.Background(), logger, &testNode{id: "n"})
+	if len(ds) != 2 {
+		t.Fatalf("expected 2 decisions, got %d", len(ds))
+	}
+
+	var recs []map[string]any
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var m map[string]any
+		if err := dec.Decode(&m); err != nil {
+			t.Fatal(err)
+		}
+		recs = append(recs, m)
+	}
+	if len(recs) != 2 {
+		t.Fatalf("expected 2 records, got %v", recs)
+	}
+	if recs[0]["level"] != "WARN" || recs[0]["policy.id"] != "w" || recs[0]["decision.reason"] != "too hot" {
+		t.Errorf("unexpected warn record %v", recs[0])
+	}
+	if recs[1]["level"] != "ERROR" || recs[1]["decision.action"] != "CancelNode" || recs[1]["decision.scope"] != "Node" {
+		t.Errorf("unexpected cancel record %v", recs[1])
+	}
+
+	buf.Reset()
+	r.SlogEvaluateLevels(context.Background(), logger, &testNode{id: "n"}, func(policy.Action) slog.Level { return slog.LevelDebug })
+	if buf.Len() != 0 {
+		t.Errorf("expected debug records to be filtered, got %s", buf.String())
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+import (
+	"slices"
+	"sync"
+)
+
+// tenants holds the per-tenant registries layered on the global registry.
+var tenants = struct {
+	mu   sync.Mutex
+	byID map[string]*Registry
+}{byID: map[string]*Registry{}}
+
+// TenantRegistry returns the registry for tenantID, creating it on first use.
+//
+// A tenant registry is layered on the global registry: it sees every global
+// policy (including ones registered later) plus its own. A tenant policy
+// whose ID matches a global one overrides it for that tenant only. Policies
+// registered on the returned Registry never affect the global registry or
+// other tenants.
+func TenantRegistry(tenantID string) *Registry {
+	tenants.mu.Lock()
+	defer tenants.mu.Unlock()
+
+	if r, ok := tenants.byID[tenantID]; ok {
+		return r
+	}
+	r := defaultRegistry.clone()
+	r.parent = defaultRegistry
+	defaultRegistry.mu.Lock()
+	defaultRegistry.children = append(defaultRegistry.children, r)
+	defaultRegistry.mu.Unlock()
+
+	tenants.byID[tenantID] = r
+	return r
+}
+
+// DropTenant forgets tenantID's registry so that it can be garbage collected,
+// and reports whether one existed. A later TenantRegistry call for the same
+// ID starts from an empty registry. Stop using a dropped Registry: it is no
+// longer notified of global changes, so its CachedEvaluate results may go
+// stale.
+func DropTenant(tenantID string) bool {
+	tenants.mu.Lock()
+	defer tenants.mu.Unlock()
+
+	r, ok := tenants.byID[tenantID]
+	if !ok {
+		return false
+	}
+	delete(tenants.byID, tenantID)
+	defaultRegistry.mu.Lock()
+	defaultRegistry.children = slices.DeleteFunc(defaultRegistry.children, func(c *Registry) bool { return c == r })
+	defaultRegistry.mu.Unlock()
+	return true
+}
+
+// RegisterForTenant adds p to tenantID's registry only. It returns
+// ErrNilPolicy if p is nil and ErrNilPolicyFunc for an invalid FuncPolicy.
+func RegisterForTenant(tenantID string, p Policy) error {
+	if err := validatePolicy(p); err != nil {
+		return err
+	}
+	TenantRegistry(tenantID).Register(p)
+	return nil
+}
+
+// EvaluateForTenant evaluates n against tenantID's policies layered on the
+// global ones. Tenants without a registry fall through to the global
+// registry, exactly as Evaluate.
+func EvaluateForTenant(tenantID string, n Node) []Decision {
+	tenants.mu.Lock()
+	r, ok := tenants.byID[tenantID]
+	tenants.mu.Unlock()
+
+	if !ok {
+		return Evaluate(n)
+	}
+	return r.Evaluate(n)
+}
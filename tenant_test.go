@@ -0,0 +1,106 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+// namedWarnPolicy warns only for nodes with the given name, so tests sharing
+// the global registry do not observe each other's policies.
+type namedWarnPolicy struct {
+	id, name, label string
+	prio            int
+}
+
+func (p namedWarnPolicy) ID() string               { return p.id }
+func (p namedWarnPolicy) Priority() int            { return p.prio }
+func (p namedWarnPolicy) Match(n policy.Node) bool { return n.Name() == p.name }
+func (p namedWarnPolicy) Check(policy.Node) []policy.Decision {
+	return []policy.Decision{{PolicyID: p.id, Action: policy.ActionWarn, Reason: policy.Reason(p.label)}}
+}
+
+// withPrefix keeps only decisions whose PolicyID starts with prefix, hiding
+// policies other tests registered globally.
+func withPrefix(ds []policy.Decision, prefix string) []policy.Decision {
+	var out []policy.Decision
+	for _, d := range ds {
+		if strings.HasPrefix(d.PolicyID, prefix) {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+func TestTenantRegistryLayering(t *testing.T) {
+	policy.RegisterPolicy(namedWarnPolicy{id: "tenant.base", name: "tenant-test", label: "global", prio: 1})
+	if err := policy.RegisterForTenant("acme", namedWarnPolicy{id: "tenant.extra", name: "tenant-test", label: "acme", prio: 2}); err != nil {
+		t.Fatalf("RegisterForTenant: %v", err)
+	}
+	n := &testNode{id: "t1", name: "tenant-test"}
+
+	if ds := withPrefix(policy.EvaluateForTenant("acme", n), "tenant."); len(ds) != 2 || ds[0].PolicyID != "tenant.base" || ds[1].PolicyID != "tenant.extra" {
+		t.Fatalf("acme decisions = %+v", ds)
+	}
+	if ds := withPrefix(policy.EvaluateForTenant("other", n), "tenant."); len(ds) != 1 || ds[0].PolicyID != "tenant.base" {
+		t.Fatalf("unknown tenant should fall through to global: %+v", ds)
+	}
+	if ds := withPrefix(policy.Evaluate(n), "tenant."); len(ds) != 1 {
+		t.Fatalf("tenant policy leaked into global registry: %+v", ds)
+	}
+
+	// Tenant override of a global policy ID.
+	policy.TenantRegistry("acme").Register(namedWarnPolicy{id: "tenant.base", name: "tenant-test", label: "acme-override", prio: 1})
+	ds := withPrefix(policy.EvaluateForTenant("acme", n), "tenant.")
+	if len(ds) != 2 || ds[0].Reason.Error() != "acme-override" {
+		t.Fatalf("tenant override not applied: %+v", ds)
+	}
+
+	// Global policies registered later are visible to existing tenants.
+	policy.RegisterPolicy(namedWarnPolicy{id: "tenant.late", name: "tenant-test", label: "global", prio: 3})
+	if ds := withPrefix(policy.EvaluateForTenant("acme", n), "tenant."); len(ds) != 3 {
+		t.Fatalf("late global policy not visible to tenant: %+v", ds)
+	}
+
+	if err := policy.RegisterForTenant("acme", nil); !errors.Is(err, policy.ErrNilPolicy) {
+		t.Fatalf("nil policy: got %v", err)
+	}
+}
+
+func TestTenantVersionAndDrop(t *testing.T) {
+	r := policy.TenantRegistry("drop-me")
+	r.Register(namedWarnPolicy{id: "drop.own", name: "drop-test", prio: 1})
+	before := r.Version()
+	policy.RegisterPolicy(namedWarnPolicy{id: "drop.global", name: "drop-test", prio: 2})
+	if r.Version() == before {
+		t.Fatal("tenant Version did not advance on a global change")
+	}
+
+	if !policy.DropTenant("drop-me") || policy.DropTenant("drop-me") {
+		t.Fatal("DropTenant should report true once, then false")
+	}
+	fresh := policy.TenantRegistry("drop-me")
+	if fresh == r {
+		t.Fatal("dropped tenant registry was returned again")
+	}
+	if ds := withPrefix(policy.EvaluateForTenant("drop-me", &testNode{id: "d", name: "drop-test"}), "drop."); len(ds) != 1 || ds[0].PolicyID != "drop.global" {
+		t.Fatalf("fresh tenant decisions = %+v", ds)
+	}
+	policy.DropTenant("drop-me")
+}
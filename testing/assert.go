@@ -0,0 +1,108 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package policytest provides test helpers for authors of ccxpolicy policies
+// and enforcers. Import it from _test.go files only:
+//
+//	import policytest "github.com/ArieDeha/ccxpolicy/testing"
+package policytest
+
+import (
+	"reflect"
+	"testing"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+// stubNode is a minimal policy.Node with fixed params and no parent.
+type stubNode struct {
+	label  string
+	params map[string]any
+}
+
+func (n stubNode) ID() string             { return "policytest/" + n.label }
+func (n stubNode) Name() string           { return n.label }
+func (n stubNode) Params() map[string]any { return n.params }
+func (stubNode) Parent() policy.Node      { return nil }
+func (n stubNode) Root() policy.Node      { return n }
+
+// hostileNodes are the degenerate inputs a policy must tolerate.
+func hostileNodes() []stubNode {
+	return []stubNode{
+		{label: "nil-params", params: nil},
+		{label: "empty-params", params: map[string]any{}},
+		{label: "nil-values", params: map[string]any{"": nil, "key": nil, "value": nil}},
+	}
+}
+
+// AssertPolicyMatchSafe calls p.Match with nodes whose Params are nil, empty,
+// or hold only nil values, and fails t if any call panics. The returned
+// booleans are not checked.
+func AssertPolicyMatchSafe(t testing.TB, p policy.Policy) {
+	t.Helper()
+	for _, n := range hostileNodes() {
+		if v, panicked := catch(func() { p.Match(n) }); panicked {
+			t.Errorf("policy %q: Match panicked on %s node: %v", p.ID(), n.label, v)
+		}
+	}
+}
+
+// AssertPolicyCheckSafe calls p.Check with the same degenerate nodes as
+// AssertPolicyMatchSafe (regardless of Match) and fails t if any call panics.
+func AssertPolicyCheckSafe(t testing.TB, p policy.Policy) {
+	t.Helper()
+	for _, n := range hostileNodes() {
+		if v, panicked := catch(func() { p.Check(n) }); panicked {
+			t.Errorf("policy %q: Check panicked on %s node: %v", p.ID(), n.label, v)
+		}
+	}
+}
+
+// StateInspector is implemented by Enforcers whose accumulated effects can be
+// read back, so AssertReplayIdempotent can compare them between runs.
+type StateInspector interface {
+	// State returns a snapshot of the effects applied so far. It must not
+	// share mutable memory with the Enforcer.
+	State() any
+}
+
+// AssertReplayIdempotent enforces ds on e twice with policy.Enforce and fails
+// t if the second run changed e's State (compared with reflect.DeepEqual),
+// i.e. if replaying the same Decisions was not a no-op. It fails t without
+// enforcing anything if e does not implement StateInspector.
+func AssertReplayIdempotent(t testing.TB, e policy.Enforcer, ds []policy.Decision) {
+	t.Helper()
+	si, ok := e.(StateInspector)
+	if !ok {
+		t.Errorf("enforcer %T does not implement StateInspector", e)
+		return
+	}
+	policy.Enforce(e, ds)
+	first := si.State()
+	policy.Enforce(e, ds)
+	if second := si.State(); !reflect.DeepEqual(first, second) {
+		t.Errorf("enforcer %T: replaying %d decisions changed state:\nfirst:  %v\nsecond: %v", e, len(ds), first, second)
+	}
+}
+
+// catch runs fn and reports the value of any panic it raised.
+func catch(fn func()) (v any, panicked bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			v, panicked = r, true
+		}
+	}()
+	fn()
+	return nil, false
+}
@@ -0,0 +1,100 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policytest_test
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"testing"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+	policytest "github.com/ArieDeha/ccxpolicy/testing"
+)
+
+// recordingTB captures failures instead of failing the enclosing test.
+type recordingTB struct {
+	testing.TB
+	errs []string
+}
+
+func (r *recordingTB) Helper() {}
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.errs = append(r.errs, fmt.Sprintf(format, args...))
+}
+
+// fragilePolicy type-asserts params without checking.
+type fragilePolicy struct{}
+
+func (fragilePolicy) ID() string               { return "fragile" }
+func (fragilePolicy) Priority() int            { return 0 }
+func (fragilePolicy) Match(n policy.Node) bool { return n.Params()["key"].(int) > 0 }
+func (fragilePolicy) Check(n policy.Node) []policy.Decision {
+	_ = n.Params()["key"].(string)
+	return nil
+}
+
+func TestAssertSafeDetectsPanics(t *testing.T) {
+	rec := &recordingTB{TB: t}
+	policytest.AssertPolicyMatchSafe(rec, fragilePolicy{})
+	policytest.AssertPolicyCheckSafe(rec, fragilePolicy{})
+	if len(rec.errs) != 6 {
+		t.Fatalf("expected 6 failures (3 nodes x Match/Check), got %d: %v", len(rec.errs), rec.errs)
+	}
+}
+
+func TestAssertSafeAcceptsBuiltins(t *testing.T) {
+	for _, p := range []policy.Policy{
+		policy.NewParamRangePolicy("range", 0, "key", 0, 1, policy.ActionAdjust),
+		policy.NewMutualExclusionPolicy("mutex", 0, "Migrate"),
+	} {
+		policytest.AssertPolicyMatchSafe(t, p)
+		policytest.AssertPolicyCheckSafe(t, p)
+	}
+}
+
+// setEnforcer records warned policy IDs as a set; replays are no-ops.
+type setEnforcer struct{ warned map[string]bool }
+
+func (e *setEnforcer) Adjust(policy.Scope, func(map[string]any)) {}
+func (e *setEnforcer) Cancel(policy.Scope, error)                {}
+func (e *setEnforcer) Warn(id string, _ error)                   { e.warned[id] = true }
+func (e *setEnforcer) State() any                                { return maps.Clone(e.warned) }
+
+// logEnforcer appends every warning, so replays change its state.
+type logEnforcer struct{ warned []string }
+
+func (e *logEnforcer) Adjust(policy.Scope, func(map[string]any)) {}
+func (e *logEnforcer) Cancel(policy.Scope, error)                {}
+func (e *logEnforcer) Warn(id string, _ error)                   { e.warned = append(e.warned, id) }
+func (e *logEnforcer) State() any                                { return slices.Clone(e.warned) }
+
+func TestAssertReplayIdempotent(t *testing.T) {
+	ds := []policy.Decision{{PolicyID: "a", Action: policy.ActionWarn}}
+
+	policytest.AssertReplayIdempotent(t, &setEnforcer{warned: map[string]bool{}}, ds)
+
+	rec := &recordingTB{TB: t}
+	policytest.AssertReplayIdempotent(rec, &logEnforcer{}, ds)
+	if len(rec.errs) != 1 {
+		t.Fatalf("expected 1 failure for appending enforcer, got %v", rec.errs)
+	}
+
+	rec = &recordingTB{TB: t}
+	policytest.AssertReplayIdempotent(rec, struct{ policy.Enforcer }{&logEnforcer{}}, ds)
+	if len(rec.errs) != 1 {
+		t.Fatalf("expected 1 failure for enforcer without State, got %v", rec.errs)
+	}
+}
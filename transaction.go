@@ -0,0 +1,64 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Transaction is a unit of all-or-nothing enforcement, typically backed by a
+// database transaction or a staged change set in the host.
+type Transaction interface {
+	EnforcerE
+	// Commit makes every effect applied through the transaction permanent.
+	Commit() error
+	// Rollback discards every effect applied through the transaction.
+	Rollback() error
+}
+
+// TransactionalEnforcer is implemented by hosts able to apply decisions
+// atomically.
+type TransactionalEnforcer interface {
+	// Begin starts a new Transaction.
+	Begin() (Transaction, error)
+}
+
+// EnforceTransactional applies ds atomically through a Transaction obtained
+// from te.Begin().
+//
+// Decisions are mapped as in Enforce (honouring Stop). If any call returns an
+// error, the transaction is rolled back and the error is returned, joined with
+// the Rollback error if that fails too. Otherwise the transaction is committed
+// and Commit's error (if any) is returned.
+func EnforceTransactional(te TransactionalEnforcer, ds []Decision) error {
+	tx, err := te.Begin()
+	if err != nil {
+		return fmt.Errorf("ccxpolicy: begin transaction: %w", err)
+	}
+	for _, d := range ds {
+		if err := applyDecisionE(tx, d); err != nil {
+			err = fmt.Errorf("ccxpolicy: policy %q: %w", d.PolicyID, err)
+			if rerr := tx.Rollback(); rerr != nil {
+				return errors.Join(err, fmt.Errorf("ccxpolicy: rollback: %w", rerr))
+			}
+			return err
+		}
+		if d.Stop {
+			break
+		}
+	}
+	return tx.Commit()
+}
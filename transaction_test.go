@@ -0,0 +1,86 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"errors"
+	"testing"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+var errCancelFailed = errors.New("cancel failed")
+
+// memTx stages warnings and publishes them to its store on Commit.
+type memTx struct {
+	store      *memStore
+	staged     []string
+	failCancel bool
+}
+
+func (tx *memTx) Adjust(policy.Scope, func(map[string]any)) error { return nil }
+func (tx *memTx) Cancel(policy.Scope, error) error {
+	if tx.failCancel {
+		return errCancelFailed
+	}
+	tx.staged = append(tx.staged, "cancel")
+	return nil
+}
+func (tx *memTx) Warn(id string, _ error) error {
+	tx.staged = append(tx.staged, id)
+	return nil
+}
+func (tx *memTx) Commit() error {
+	tx.store.committed = append(tx.store.committed, tx.staged...)
+	return nil
+}
+func (tx *memTx) Rollback() error {
+	tx.store.rollbacks++
+	return nil
+}
+
+type memStore struct {
+	committed  []string
+	rollbacks  int
+	failCancel bool
+}
+
+func (s *memStore) Begin() (policy.Transaction, error) {
+	return &memTx{store: s, failCancel: s.failCancel}, nil
+}
+
+func TestEnforceTransactional(t *testing.T) {
+	ds := []policy.Decision{
+		{PolicyID: "W", Action: policy.ActionWarn},
+		{PolicyID: "C", Action: policy.ActionCancelNode},
+	}
+
+	ok := &memStore{}
+	if err := policy.EnforceTransactional(ok, ds); err != nil {
+		t.Fatalf("EnforceTransactional: %v", err)
+	}
+	if len(ok.committed) != 2 || ok.rollbacks != 0 {
+		t.Fatalf("commit path: %+v", ok)
+	}
+
+	bad := &memStore{failCancel: true}
+	err := policy.EnforceTransactional(bad, ds)
+	if !errors.Is(err, errCancelFailed) {
+		t.Fatalf("expected cancel error, got %v", err)
+	}
+	if len(bad.committed) != 0 || bad.rollbacks != 1 {
+		t.Fatalf("failed run must roll back and commit nothing: %+v", bad)
+	}
+}
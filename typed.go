@@ -0,0 +1,69 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+// TypedPolicy is the generic counterpart of Policy for hosts with a single
+// concrete node type N. Match and Check receive N directly, removing the
+// type-assertion boilerplate from policy implementations.
+type TypedPolicy[N Node] interface {
+	ID() string
+	Priority() int
+	Match(n N) bool
+	Check(n N) []Decision
+}
+
+// TypedRegistry is a Registry that only accepts TypedPolicy[N] and only
+// evaluates nodes of type N, so mismatches are caught at compile time.
+// Ordering, Stop handling, check limits, and hooks behave exactly as in
+// Registry.
+type TypedRegistry[N Node] struct {
+	r *Registry
+}
+
+// NewTypedRegistry returns an empty TypedRegistry configured by opts.
+func NewTypedRegistry[N Node](opts ...RegistryOption) *TypedRegistry[N] {
+	return &TypedRegistry[N]{r: New(opts...)}
+}
+
+// Register adds p to the registry.
+func (t *TypedRegistry[N]) Register(p TypedPolicy[N]) {
+	t.r.Register(typedPolicy[N]{p})
+}
+
+// Evaluate runs the matching policies against n; see Registry.Evaluate.
+func (t *TypedRegistry[N]) Evaluate(n N) []Decision {
+	return t.r.Evaluate(n)
+}
+
+// typedPolicy adapts a TypedPolicy[N] to Policy.
+type typedPolicy[N Node] struct {
+	p TypedPolicy[N]
+}
+
+func (a typedPolicy[N]) ID() string    { return a.p.ID() }
+func (a typedPolicy[N]) Priority() int { return a.p.Priority() }
+
+func (a typedPolicy[N]) Match(n Node) bool {
+	tn, ok := n.(N)
+	return ok && a.p.Match(tn)
+}
+
+func (a typedPolicy[N]) Check(n Node) []Decision {
+	tn, ok := n.(N)
+	if !ok {
+		return nil
+	}
+	return a.p.Check(tn)
+}
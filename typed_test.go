@@ -0,0 +1,47 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"testing"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+// qualityCap works on *testNode directly, without type assertions.
+type qualityCap struct{ prio int }
+
+func (qualityCap) ID() string             { return "cap" }
+func (q qualityCap) Priority() int        { return q.prio }
+func (qualityCap) Match(n *testNode) bool { return n.params["q"] != nil }
+func (qualityCap) Check(n *testNode) []policy.Decision {
+	if n.params["q"].(int) <= 1080 {
+		return nil
+	}
+	return []policy.Decision{{PolicyID: "cap", Action: policy.ActionAdjust, Adjust: func(m map[string]any) { m["q"] = 1080 }}}
+}
+
+func TestTypedRegistry(t *testing.T) {
+	r := policy.NewTypedRegistry[*testNode]()
+	r.Register(qualityCap{})
+
+	ds := r.Evaluate(&testNode{id: "n", params: map[string]any{"q": 1440}})
+	if len(ds) != 1 || ds[0].PolicyID != "cap" {
+		t.Fatalf("decisions %+v", ds)
+	}
+	if ds := r.Evaluate(&testNode{id: "n", params: map[string]any{"q": 720}}); len(ds) != 0 {
+		t.Fatalf("unexpected decisions %+v", ds)
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+import "math"
+
+// NoWatermark is returned by Watermark when ds is empty or its deciding
+// policy is not registered.
+const NoWatermark = math.MinInt
+
+// Watermark reports how far down the priority stack an evaluation descended:
+// the Priority() of the policy responsible for the first Stop decision in ds,
+// or, if none stopped evaluation, for the last decision. Policies are looked
+// up by Decision.PolicyID in r. It returns NoWatermark for an empty ds or an
+// unknown policy.
+func (r *Registry) Watermark(ds []Decision) int {
+	if len(ds) == 0 {
+		return NoWatermark
+	}
+	last := ds[len(ds)-1]
+	for _, d := range ds {
+		if d.Stop {
+			last = d
+			break
+		}
+	}
+	p, ok := r.lookup(last.PolicyID)
+	if !ok {
+		return NoWatermark
+	}
+	return p.Priority()
+}
+
+// Watermark runs Registry.Watermark against the global registry.
+func Watermark(ds []Decision) int { return defaultRegistry.Watermark(ds) }
+
+// lookup returns the registered policy with the given ID, if any, following
+// aliases left by MigratePolicy.
+func (r *Registry) lookup(id string) (Policy, bool) {
+	r.mu.RLock()
+	if to, ok := r.aliases[id]; ok {
+		id = to
+	}
+	r.mu.RUnlock()
+	for _, p := range r.snapshot() {
+		if p.ID() == id {
+			return p, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,44 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"testing"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+func TestWatermark(t *testing.T) {
+	r := policy.New()
+	r.Register(warnPolicy{id: "early", prio: 1})
+	r.Register(warnPolicy{id: "late", prio: 30})
+	n := &testNode{id: "n1", name: "N"}
+
+	if got := r.Watermark(r.Evaluate(n)); got != 30 {
+		t.Fatalf("watermark without Stop = %d, want 30", got)
+	}
+
+	r.Register(policyBStop{}) // priority 5, stops
+	if got := r.Watermark(r.Evaluate(n)); got != 5 {
+		t.Fatalf("watermark with Stop = %d, want 5", got)
+	}
+
+	if got := r.Watermark(nil); got != policy.NoWatermark {
+		t.Fatalf("empty watermark = %d", got)
+	}
+	if got := r.Watermark([]policy.Decision{{PolicyID: "unknown"}}); got != policy.NoWatermark {
+		t.Fatalf("unknown policy watermark = %d", got)
+	}
+}
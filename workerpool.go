@@ -0,0 +1,79 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+import (
+	"runtime"
+	"sync"
+)
+
+// EvaluateWithWorkerPool returns the same Decisions as Evaluate, but runs the
+// Match and Check calls of the registered policies on a pool of workers
+// goroutines created for this call, which bounds goroutine count and memory
+// while still spreading CPU-bound policies across cores. workers <= 0 means
+// runtime.GOMAXPROCS(0).
+//
+// Notes:
+//   - Policies must be safe for concurrent use with each other. Panics are
+//     recovered as in Evaluate.
+//   - Results are assembled in priority order after every policy has run, so
+//     a Stop decision still truncates the output (and Hooks.OnStop still
+//     fires) but does not prevent the later policies' Check from running.
+func (r *Registry) EvaluateWithWorkerPool(n Node, workers int) []Decision {
+	pols := r.snapshot()
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, len(pols))
+
+	results := make([][]Decision, len(pols))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for range workers {
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = r.recoveringCheck(pols[i], n)
+			}
+		}()
+	}
+	for i := range pols {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	hooks := r.currentHooks()
+	out := make([]Decision, 0, 4)
+	for i, ds := range results {
+		for _, d := range ds {
+			out = append(out, d)
+			if d.Stop {
+				if hooks.OnStop != nil {
+					hooks.OnStop(d, pols[i+1:])
+				}
+				return out
+			}
+		}
+	}
+	return out
+}
+
+// EvaluateWithWorkerPool runs Registry.EvaluateWithWorkerPool against the
+// global registry.
+func EvaluateWithWorkerPool(n Node, workers int) []Decision {
+	return defaultRegistry.EvaluateWithWorkerPool(n, workers)
+}
@@ -0,0 +1,81 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"fmt"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+// inflightPolicy records the highest number of concurrent Check calls.
+type inflightPolicy struct {
+	id        string
+	cur, peak *atomic.Int32
+}
+
+func (p inflightPolicy) ID() string             { return p.id }
+func (p inflightPolicy) Priority() int          { return 0 }
+func (p inflightPolicy) Match(policy.Node) bool { return true }
+func (p inflightPolicy) Check(policy.Node) []policy.Decision {
+	c := p.cur.Add(1)
+	for {
+		old := p.peak.Load()
+		if c <= old || p.peak.CompareAndSwap(old, c) {
+			break
+		}
+	}
+	time.Sleep(time.Millisecond)
+	p.cur.Add(-1)
+	return nil
+}
+
+func TestEvaluateWithWorkerPoolMatchesEvaluate(t *testing.T) {
+	r := policy.New()
+	for i := range 10 {
+		r.Register(warnPolicy{id: fmt.Sprintf("p%d", i), prio: 10 - i})
+	}
+	r.Register(&policy.FuncPolicy{
+		PolicyID: "stop",
+		Prio:     5,
+		MatchFn:  func(policy.Node) bool { return true },
+		CheckFn: func(policy.Node) []policy.Decision {
+			return []policy.Decision{{Action: policy.ActionCancelNode, Stop: true}}
+		},
+	})
+	n := &testNode{id: "n"}
+	want := decisionIDs(r.Evaluate(n))
+	for _, workers := range []int{0, 1, 3, 100} {
+		if got := decisionIDs(r.EvaluateWithWorkerPool(n, workers)); !slices.Equal(got, want) {
+			t.Errorf("workers=%d: got %v, want %v", workers, got, want)
+		}
+	}
+}
+
+func TestEvaluateWithWorkerPoolBoundsConcurrency(t *testing.T) {
+	r := policy.New()
+	var cur, peak atomic.Int32
+	for i := range 12 {
+		r.Register(inflightPolicy{id: fmt.Sprint(i), cur: &cur, peak: &peak})
+	}
+	r.EvaluateWithWorkerPool(&testNode{id: "n"}, 2)
+	if p := peak.Load(); p > 2 {
+		t.Fatalf("peak concurrency %d exceeds 2 workers", p)
+	}
+}
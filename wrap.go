@@ -0,0 +1,94 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+// policyWrapper is implemented by the wrappers the registry puts around
+// registered policies (priority overrides, dry-run staging, renames and
+// namespaces) and by the exported wrapping constructors such as
+// MemoizedPolicy. A wrapper embeds the policy it wraps, which hides the wrapped
+// policy's optional interfaces; asPolicy looks through it instead.
+type policyWrapper interface {
+	Policy
+	// unwrapPolicy returns the wrapped policy.
+	unwrapPolicy() Policy
+	// rewrapPolicy returns the same wrapper around inner.
+	rewrapPolicy(inner Policy) Policy
+	// wrapNode returns the node the wrapped policy sees in place of n.
+	wrapNode(n Node) Node
+	// wrapDecisions applies the wrapper's rewriting to Decisions returned by
+	// the wrapped policy, without modifying ds.
+	wrapDecisions(ds []Decision) []Decision
+}
+
+// WrappedPolicy is implemented by Policy wrappers defined outside this
+// package, such as the tracing wrapper of ccxpolicy/otel. UnwrapPolicy
+// returns the wrapped policy, through which the registry finds the optional
+// interfaces (PrecisePriority, Explainable, Describable, ...) the wrapper does
+// not implement itself, so wrapping a policy does not change how it is
+// ordered or explained. A wrapper that runs the wrapped policy's Check should
+// also implement ContextPolicy via CheckPolicyContext, since Check is
+// otherwise used even when the wrapped policy is a ContextPolicy.
+type WrappedPolicy interface {
+	Policy
+	UnwrapPolicy() Policy
+}
+
+// UnwrapPolicy returns the policy p wraps, or nil if p is not a wrapper. The
+// wrappers this package creates (SwapPriorities, MigratePolicy,
+// RegisterDryRun, MemoizedPolicy, ...) and WrappedPolicy implementations are
+// unwrapped, so a caller can recognise its own policy types after the
+// registry has wrapped them.
+func UnwrapPolicy(p Policy) Policy {
+	inner, _, _ := unwrapOnce(p, nil)
+	return inner
+}
+
+// asPolicy returns the first policy in p's wrapper chain, starting with p
+// itself, that is a T, in the manner of errors.As.
+func asPolicy[T any](p Policy) (T, bool) {
+	t, _, ok := asPolicyNode[T](p, nil)
+	return t, ok
+}
+
+// unwrapOnce returns the policy p wraps and n as that policy sees it. ok is
+// false if p is not a wrapper.
+func unwrapOnce(p Policy, n Node) (inner Policy, innerNode Node, ok bool) {
+	switch w := p.(type) {
+	case policyWrapper:
+		if n != nil {
+			n = w.wrapNode(n)
+		}
+		return w.unwrapPolicy(), n, true
+	case WrappedPolicy:
+		return w.UnwrapPolicy(), n, true
+	}
+	return nil, nil, false
+}
+
+// asPolicyNode is asPolicy that also returns n as the found policy sees it,
+// i.e. passed through the wrapNode of every wrapper above it.
+func asPolicyNode[T any](p Policy, n Node) (T, Node, bool) {
+	for p != nil {
+		if t, ok := p.(T); ok {
+			return t, n, true
+		}
+		var ok bool
+		if p, n, ok = unwrapOnce(p, n); !ok {
+			break
+		}
+	}
+	var zero T
+	return zero, nil, false
+}
@@ -0,0 +1,155 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+// featurePolicy implements every optional Policy interface except
+// PrecisePriority.
+type featurePolicy struct {
+	warnPolicy
+	resets int
+}
+
+func (p *featurePolicy) Reset()                     { p.resets++ }
+func (p *featurePolicy) Describe() string           { return "feature" }
+func (p *featurePolicy) Explain(policy.Node) string { return "because" }
+func (p *featurePolicy) PolicyVersion() int         { return policy.PolicyAPIVersion + 1 }
+func (p *featurePolicy) DependsOn() []string        { return []string{"dep"} }
+func (p *featurePolicy) CheckContext(context.Context, policy.Node) []policy.Decision {
+	return []policy.Decision{{PolicyID: p.id, Action: policy.ActionCancelNode, Reason: policy.Reason("ctx")}}
+}
+
+// TestWrappedPoliciesKeepOptionalInterfaces checks that the wrappers the
+// registry puts around a policy do not hide its optional interfaces.
+func TestWrappedPoliciesKeepOptionalInterfaces(t *testing.T) {
+	cases := map[string]func(fp *featurePolicy) (r *policy.Registry, id string){
+		"reprioritized": func(fp *featurePolicy) (*policy.Registry, string) {
+			r := policy.New()
+			r.Register(fp)
+			r.Register(warnPolicy{id: "other", prio: 5})
+			if err := r.SwapPriorities(fp.id, "other"); err != nil {
+				t.Fatal(err)
+			}
+			return r, fp.id
+		},
+		"dry-run": func(fp *featurePolicy) (*policy.Registry, string) {
+			r := policy.New()
+			if err := r.RegisterDryRun(fp); err != nil {
+				t.Fatal(err)
+			}
+			return r, fp.id
+		},
+		"memoized": func(fp *featurePolicy) (*policy.Registry, string) {
+			r := policy.New()
+			r.Register(policy.MemoizedPolicy(fp, policy.Node.ID))
+			return r, fp.id
+		},
+		"redacted": func(fp *featurePolicy) (*policy.Registry, string) {
+			r := policy.New()
+			r.Register(policy.RedactPolicy(fp, []string{"token"}))
+			return r, fp.id
+		},
+		"override": func(fp *featurePolicy) (*policy.Registry, string) {
+			r := policy.New()
+			r.Register(policy.Override(fp, warnPolicy{id: "base"}))
+			return r, fp.id
+		},
+		"escalating": func(fp *featurePolicy) (*policy.Registry, string) {
+			r := policy.New()
+			r.Register(policy.NewEscalatingPolicy(fp, 1, 2, time.Minute))
+			return r, fp.id
+		},
+		"migrated dry-run": func(fp *featurePolicy) (*policy.Registry, string) {
+			r := policy.New()
+			if err := r.RegisterDryRun(fp); err != nil {
+				t.Fatal(err)
+			}
+			if err := r.MigratePolicy(fp.id, "renamed"); err != nil {
+				t.Fatal(err)
+			}
+			return r, "renamed"
+		},
+	}
+	for name, wrap := range cases {
+		fp := &featurePolicy{warnPolicy: warnPolicy{id: "feat", label: "plain"}}
+		r, id := wrap(fp)
+		n := &testNode{id: "n"}
+		p, ok := r.GetPolicy(id)
+		if !ok {
+			t.Fatalf("%s: %q not registered", name, id)
+		}
+
+		if got := policy.PolicyDescription(p); got != "feature" {
+			t.Errorf("%s: Describable hidden, got %q", name, got)
+		}
+		if got := r.EvaluateWithExplanations(n)[id]; got != "because" {
+			t.Errorf("%s: Explainable hidden, got %q", name, got)
+		}
+		if err := policy.CheckPolicyAPIVersion(p); !errors.Is(err, policy.ErrPolicyAPIMismatch) {
+			t.Errorf("%s: VersionedPolicy hidden, got %v", name, err)
+		}
+		var dot strings.Builder
+		if err := r.ExportDOT(&dot); err != nil || !strings.Contains(dot.String(), `"dep"`) {
+			t.Errorf("%s: PolicyDependencies hidden: %v\n%s", name, err, dot.String())
+		}
+		r.ResetAllPolicies()
+		if fp.resets != 1 {
+			t.Errorf("%s: Resettable hidden, %d resets", name, fp.resets)
+		}
+		ds, err := r.EvaluateContext(context.Background(), n)
+		var viaCtx bool
+		for _, d := range ds {
+			viaCtx = viaCtx || (d.PolicyID == id && d.Reason.Error() == "ctx")
+		}
+		if err != nil || !viaCtx {
+			t.Errorf("%s: ContextPolicy hidden: %v %+v", name, err, ds)
+		}
+	}
+}
+
+// externalWrapper is a WrappedPolicy defined outside the package.
+type externalWrapper struct{ policy.Policy }
+
+func (w externalWrapper) UnwrapPolicy() policy.Policy { return w.Policy }
+
+// TestWrappersKeepPrecisePriority checks that wrapping a policy does not move
+// it in the evaluation order.
+func TestWrappersKeepPrecisePriority(t *testing.T) {
+	wrappers := map[string]func(policy.Policy) policy.Policy{
+		"NewEscalatingPolicy": func(p policy.Policy) policy.Policy { return policy.NewEscalatingPolicy(p, 1, 2, time.Minute) },
+		"MemoizedPolicy":      func(p policy.Policy) policy.Policy { return policy.MemoizedPolicy(p, policy.Node.ID) },
+		"Override":            func(p policy.Policy) policy.Policy { return policy.Override(p, warnPolicy{id: "base"}) },
+		"WrappedPolicy":       func(p policy.Policy) policy.Policy { return externalWrapper{p} },
+		"RedactPolicy":        func(p policy.Policy) policy.Policy { return policy.RedactPolicy(p, []string{"token"}) },
+	}
+	for name, wrap := range wrappers {
+		r := policy.New()
+		r.Register(wrap(precisePolicy{warnPolicy: warnPolicy{id: "a"}, precise: 1.9}))
+		r.Register(precisePolicy{warnPolicy: warnPolicy{id: "b"}, precise: 1.5})
+		if got := decisionIDs(r.Evaluate(&testNode{id: "n"})); !reflect.DeepEqual(got, []string{"b", "a"}) {
+			t.Errorf("%s: order = %v, want [b a]", name, got)
+		}
+	}
+}
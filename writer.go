@@ -0,0 +1,94 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+)
+
+// ErrWriterClosed is returned by DecisionBatchWriter methods after Close.
+var ErrWriterClosed = errors.New("ccxpolicy: decision writer closed")
+
+// DecisionBatchWriter serialises batches of Decisions as JSON lines (one object
+// per decision) into a buffered io.Writer. Each batch is encoded in memory
+// first and handed to the buffer in a single write, so a batch is never
+// interleaved with another even when the writer is shared between goroutines.
+//
+// Adjust functions are not serialisable and are omitted (a Patch is written);
+// Reason is written as its message. Call Flush to push buffered data downstream and Close when done.
+type DecisionBatchWriter struct {
+	mu     sync.Mutex
+	buf    *bufio.Writer
+	scr    bytes.Buffer
+	closed bool
+}
+
+// NewDecisionBatchWriter returns a DecisionBatchWriter writing to w.
+func NewDecisionBatchWriter(w io.Writer) *DecisionBatchWriter {
+	return &DecisionBatchWriter{buf: bufio.NewWriter(w)}
+}
+
+// Write encodes ds as JSON lines and appends them to the buffer. Data reaches
+// the underlying writer when the buffer fills, or on Flush/Close.
+func (w *DecisionBatchWriter) Write(ds []Decision) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return ErrWriterClosed
+	}
+
+	w.scr.Reset()
+	enc := json.NewEncoder(&w.scr)
+	for _, d := range ds {
+		j, err := toDecisionJSON(d)
+		if err != nil {
+			return err
+		}
+		if err := enc.Encode(j); err != nil {
+			return err
+		}
+	}
+	_, err := w.buf.Write(w.scr.Bytes())
+	return err
+}
+
+// Flush writes any buffered data to the underlying writer.
+func (w *DecisionBatchWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return ErrWriterClosed
+	}
+	return w.buf.Flush()
+}
+
+// Close flushes buffered data. The underlying writer stays open; it belongs
+// to the caller, who closes it if needed. Further calls to Write or Flush
+// return ErrWriterClosed; calling Close again is a no-op.
+func (w *DecisionBatchWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	return w.buf.Flush()
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccxpolicy_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+// countingWriter records how many Write calls reach it.
+type countingWriter struct {
+	bytes.Buffer
+	writes int
+	closed bool
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return w.Buffer.Write(p)
+}
+
+func (w *countingWriter) Close() error {
+	w.closed = true
+	return nil
+}
+
+func TestDecisionBatchWriter(t *testing.T) {
+	dst := &countingWriter{}
+	w := policy.NewDecisionBatchWriter(dst)
+
+	ds := []policy.Decision{
+		{PolicyID: "a", Action: policy.ActionWarn, Reason: policy.Reason("hot")},
+		{PolicyID: "b", Action: policy.ActionAdjust, Scope: policy.ScopeSubtree, Adjust: func(map[string]any) {}},
+	}
+	if err := w.Write(ds); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if dst.writes != 0 {
+		t.Fatalf("expected buffered output before Flush, got %d writes", dst.writes)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if dst.writes != 1 {
+		t.Fatalf("expected a single downstream write, got %d", dst.writes)
+	}
+
+	want := `{"policy_id":"a","scope":"Node","action":"Warn","reason":"hot"}
+{"policy_id":"b","scope":"Subtree","action":"Adjust"}
+`
+	if got := dst.String(); got != want {
+		t.Fatalf("output =\n%s\nwant\n%s", got, want)
+	}
+
+	if err := w.Close(); err != nil || dst.closed {
+		t.Fatalf("Close: err=%v closed=%v", err, dst.closed)
+	}
+	if err := w.Write(ds); !errors.Is(err, policy.ErrWriterClosed) {
+		t.Fatalf("Write after Close: got %v", err)
+	}
+}
+
+// ExampleDecisionBatchWriter writes a batch of decisions as JSON lines.
+func ExampleDecisionBatchWriter() {
+	w := policy.NewDecisionBatchWriter(os.Stdout)
+	_ = w.Write([]policy.Decision{
+		{PolicyID: "cap_quality", Action: policy.ActionAdjust, Reason: policy.Reason("quality above cap")},
+		{PolicyID: "safety", Action: policy.ActionCancelRoot, Scope: policy.ScopeRoot, Stop: true},
+	})
+	_ = w.Flush()
+	// Output:
+	// {"policy_id":"cap_quality","scope":"Node","action":"Adjust","reason":"quality above cap"}
+	// {"policy_id":"safety","scope":"Root","action":"CancelRoot","stop":true}
+}